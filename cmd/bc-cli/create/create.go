@@ -20,9 +20,15 @@ import (
 	"os"
 
 	"github.com/bestchains/bc-cli/pkg/account"
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/channel"
 	"github.com/bestchains/bc-cli/pkg/common"
 	"github.com/bestchains/bc-cli/pkg/depository"
+	"github.com/bestchains/bc-cli/pkg/endorsepolicy"
+	"github.com/bestchains/bc-cli/pkg/federation"
 	marketrepo "github.com/bestchains/bc-cli/pkg/market/repository"
+	"github.com/bestchains/bc-cli/pkg/network"
+	"github.com/bestchains/bc-cli/pkg/org"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
@@ -35,8 +41,14 @@ func NewCreateCmd() *cobra.Command {
 	}
 
 	// Add the subcommands for creating a depository and market repository.
-	cmd.AddCommand(depository.NewCreateDepositoryCmd())
-	cmd.AddCommand(marketrepo.NewCreateMarketRepoCmd())
+	cmd.AddCommand(common.RequireLogin(depository.NewCreateDepositoryCmd()))
+	cmd.AddCommand(common.RequireLogin(marketrepo.NewCreateMarketRepoCmd()))
+	cmd.AddCommand(common.RequireLogin(chaincodebuild.NewCCBCreateCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(channel.NewChanCreateCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(endorsepolicy.NewCreateEndorsePolicyCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(federation.NewFedCreateCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(network.NewNetworkCreateCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(org.NewOrgCreateCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
 
 	// Add the subcommand for creating an account.
 	cmd.AddCommand(account.NewCreateAccountCmd(common.Options{
@@ -21,6 +21,9 @@ import (
 
 	"github.com/bestchains/bc-cli/pkg/account"
 	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/endorsepolicy"
+	"github.com/bestchains/bc-cli/pkg/network"
+	"github.com/bestchains/bc-cli/pkg/org"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
@@ -31,5 +34,8 @@ func NewDeleteCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(account.NewDeleteAccountCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
+	cmd.AddCommand(common.RequireLogin(endorsepolicy.NewDeleteEndorsePolicyCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(network.NewNetworkDeleteCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(org.NewOrgDeleteCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
 	return cmd
 }
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package describe
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/bestchains/bc-cli/pkg/chaincode"
+	"github.com/bestchains/bc-cli/pkg/channel"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/endorsepolicy"
+	"github.com/bestchains/bc-cli/pkg/federation"
+	"github.com/bestchains/bc-cli/pkg/network"
+	"github.com/bestchains/bc-cli/pkg/org"
+)
+
+func NewDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Show details of a resource with its related resources and events",
+	}
+	cmd.AddCommand(common.RequireLogin(org.NewOrgDescribeCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(federation.NewFedDescribeCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(network.NewNetworkDescribeCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(channel.NewChanDescribeCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(chaincode.NewCCDescribeCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(endorsepolicy.NewDescribeEndorsePolicyCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	return cmd
+}
@@ -31,27 +31,48 @@ import (
 	"github.com/bestchains/bc-cli/pkg/depository"
 	"github.com/bestchains/bc-cli/pkg/endorsepolicy"
 	"github.com/bestchains/bc-cli/pkg/federation"
+	"github.com/bestchains/bc-cli/pkg/kinds"
 	"github.com/bestchains/bc-cli/pkg/network"
 	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/policy"
 	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/resource"
+	"github.com/bestchains/bc-cli/pkg/vote"
 )
 
 func NewGetCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use: "get",
+		Use:   "get KIND [NAME]...",
+		Short: "Display one or many resources",
+		Long: `Display one or many resources.
+
+KIND is the name, plural name or short name of a kind, such as fed, net, ch, cc, ccb or ep.
+The supported kinds are listed in Available Commands.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			// dedicated getters and getters of registered kinds are subcommands, so the kind is unknown
+			cmd.SilenceUsage = true
+			_, err := kinds.Registry.Lookup(args[0])
+			return err
+		},
 	}
-	cmd.AddCommand(depository.NewGetDepositoryCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
+	cmd.AddCommand(common.RequireLogin(depository.NewGetDepositoryCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
 	cmd.AddCommand(account.NewGetAccountCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(org.NewOrgGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(connProfile.NewGetConnProfileCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(federation.NewFedGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(chaincode.NewCCGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(chaincodebuild.NewCCBGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(channel.NewChanGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	//cmd.AddCommand(vote.NewVoteGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(proposal.NewProposalGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	//cmd.AddCommand(policy.NewPolicyGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(network.NewNetworkGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
-	cmd.AddCommand(endorsepolicy.NewGetEndorsePolicyCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}))
+	cmd.AddCommand(common.RequireLogin(org.NewOrgGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(connProfile.NewGetConnProfileCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(connProfile.NewGetCertsCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(federation.NewFedGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(chaincode.NewCCGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(chaincodebuild.NewCCBGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(channel.NewChanGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(vote.NewVoteGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(proposal.NewProposalGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(policy.NewPolicyGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(network.NewNetworkGetCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	cmd.AddCommand(common.RequireLogin(endorsepolicy.NewGetEndorsePolicyCmd(common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}})))
+	// kinds without a dedicated getter are got by the generic one
+	resource.AddGetCmds(cmd, common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}, kinds.Registry)
 	return cmd
 }
@@ -24,13 +24,25 @@ import (
 	"os"
 	"path"
 
+	"github.com/bestchains/bc-cli/cmd/bc-cli/check"
 	"github.com/bestchains/bc-cli/cmd/bc-cli/create"
 	delcmd "github.com/bestchains/bc-cli/cmd/bc-cli/delete"
+	"github.com/bestchains/bc-cli/cmd/bc-cli/deploy"
+	"github.com/bestchains/bc-cli/cmd/bc-cli/describe"
+	"github.com/bestchains/bc-cli/cmd/bc-cli/dev"
 	"github.com/bestchains/bc-cli/cmd/bc-cli/get"
+	"github.com/bestchains/bc-cli/cmd/bc-cli/upgrade"
 	"github.com/bestchains/bc-cli/pkg/auth"
+	"github.com/bestchains/bc-cli/pkg/channel"
 	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/federation"
+	"github.com/bestchains/bc-cli/pkg/tree"
+	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
+	"github.com/bestchains/bc-cli/pkg/vote"
+	"github.com/bestchains/bc-cli/pkg/wait"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/component-base/version"
 	"k8s.io/klog/v2"
 )
@@ -54,18 +66,28 @@ func NewCmd() *cobra.Command {
 	cmd.PersistentFlags().String("client-secret", "61324af0-1234-4f61-b110-ef57013267d6", "oidc client secret")
 
 	ConfigFileFullPath := cmd.PersistentFlags().String("config", common.ConfigFilePath, "config file")
+	cmd.PersistentFlags().DurationVar(&uhttp.DefaultClientOptions.Timeout, "request-timeout", uhttp.DefaultClientOptions.Timeout, "The length of time to wait before giving up on a single request to bc-saas servers. Zero means no timeout")
+	cmd.PersistentFlags().IntVar(&uhttp.DefaultClientOptions.MaxRetries, "max-retries", uhttp.DefaultClientOptions.MaxRetries, "The max number of retries of idempotent requests on connection errors or 5xx responses")
+	// not bound to viper on purpose, skipping tls verification must be opted in for each invocation
+	cmd.PersistentFlags().BoolVar(&common.InsecureSkipTLSVerify, "insecure-skip-tls-verify", false, "If true, the server's certificate will not be checked for validity. This will make your HTTPS connections insecure")
 	_ = viper.BindPFlag("auth.issuerurl", cmd.PersistentFlags().Lookup("issuer-url"))
 	_ = viper.BindPFlag("auth.enable", cmd.PersistentFlags().Lookup("enable-auth"))
 	_ = viper.BindPFlag("cluster.server", cmd.PersistentFlags().Lookup("master-url"))
 	_ = viper.BindPFlag("auth.clientid", cmd.PersistentFlags().Lookup("client-id"))
 	_ = viper.BindPFlag("auth.clientsecret", cmd.PersistentFlags().Lookup("client-secret"))
 
-	var config *common.Config
+	config := &common.Config{}
 	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) (err error) {
-		config, err = loadConfig(*ConfigFileFullPath)
+		loaded, err := loadConfig(*ConfigFileFullPath)
 		if err != nil {
 			return err
 		}
+		*config = *loaded
+		// only commands which talk to remote services need a valid token,
+		// so local commands still work when the issuer is unreachable.
+		if !common.LoginRequired(cmd) {
+			return nil
+		}
 		configGet, err := auth.Auth(cmd.Context(), &config.Auth)
 		if err != nil {
 			return err
@@ -94,16 +116,31 @@ func NewCmd() *cobra.Command {
 		return nil
 	}
 
+	option := common.Options{IOStreams: genericclioptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr}}
 	cmd.AddCommand(create.NewCreateCmd())
 	cmd.AddCommand(get.NewGetCmd())
 	cmd.AddCommand(delcmd.NewDeleteCmd())
+	cmd.AddCommand(describe.NewDescribeCmd())
+	cmd.AddCommand(deploy.NewDeployCmd())
+	cmd.AddCommand(upgrade.NewUpgradeCmd())
+	cmd.AddCommand(check.NewCheckCmd())
+	cmd.AddCommand(auth.NewLoginCmd(option, &config.Auth))
+	cmd.AddCommand(auth.NewLogoutCmd(option, &config.Auth))
+	cmd.AddCommand(common.RequireLogin(auth.NewWhoamiCmd(option, &config.Auth)))
+	cmd.AddCommand(common.RequireLogin(federation.NewFedCmd(option)))
+	cmd.AddCommand(common.RequireLogin(channel.NewChannelCmd(option)))
+	cmd.AddCommand(common.RequireLogin(vote.NewVoteCmd(option)))
+	cmd.AddCommand(common.RequireLogin(tree.NewTreeCmd(option)))
+	cmd.AddCommand(common.RequireLogin(wait.NewWaitCmd(option)))
+	cmd.AddCommand(dev.NewDevCmd(option))
 	cmd.AddCommand(newCmdVersion())
 	return cmd
 }
 
 func main() {
+	// the error is already printed, scripts such as those running `bc-cli wait` only need the exit code
 	if err := NewCmd().Execute(); err != nil {
-		panic(err)
+		os.Exit(1)
 	}
 }
 
@@ -25,8 +25,12 @@ import (
 
 	"github.com/bestchains/bestchains-contracts/library"
 	"github.com/bestchains/bestchains-contracts/library/context"
+
+	"github.com/bestchains/bc-cli/pkg/client"
 )
 
+var _ client.Signer = (*Account)(nil)
+
 // Account represents a user account with an address and private key
 type Account struct {
 	Address    string `json:"address"`
@@ -84,3 +88,8 @@ func (account *Account) GenerateAndSignMessage(nonce uint64, args ...string) (st
 	// return the base64-encoded string representation of the message
 	return msg.Base64EncodedStr()
 }
+
+// GetAddress returns the address of the account
+func (account *Account) GetAddress() string {
+	return account.Address
+}
@@ -18,7 +18,7 @@ package auth
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -67,38 +67,70 @@ const (
 `
 )
 
+// ErrNotLoggedIn is returned when a command requires a valid token but there is none.
+var ErrNotLoggedIn = errors.New("you are not logged in, please run `bc-cli login` first")
+
+// Auth verifies the ID token stored in the config, refreshing it when it has expired.
+// It never starts an interactive login, ErrNotLoggedIn is returned if no valid token is available.
 func Auth(ctx context.Context, config *common.AuthConfig) (authConfig *common.AuthConfig, err error) {
 	if !config.Enable {
 		return config, nil
 	}
 	enableAuth = true
+	if config.IDToken == "" {
+		return nil, ErrNotLoggedIn
+	}
 	var client *client
 	client, err = newClient(ctx, *config)
 	if err != nil {
 		return nil, err
 	}
-	if config.Expiry != 0 && config.IDToken != "" {
-		if time.Now().Before(time.Unix(config.Expiry, 0)) {
-			klog.V(2).Infoln("Parse ID token from config file and try to verify it is valid...")
-			err = client.verifyIDToken(ctx)
-		} else {
-			klog.V(2).Infoln("ID token has expired, try to refresh it...")
-			err = client.refresh(ctx)
-		}
-
-		if err == nil {
-			idToken = config.IDToken
-			return &client.AuthConfig, nil
-		}
+	if config.Expiry != 0 && time.Now().Before(time.Unix(config.Expiry, 0)) {
+		klog.V(2).Infoln("Parse ID token from config file and try to verify it is valid...")
+		err = client.verifyIDToken(ctx)
+	} else {
+		klog.V(2).Infoln("ID token has expired, try to refresh it...")
+		err = client.refresh(ctx)
+	}
+	if err != nil {
 		klog.Errorf("failed to verify or refresh ID token: %v", err)
+		return nil, fmt.Errorf("%w: %s", ErrNotLoggedIn, err)
+	}
+	idToken = client.AuthConfig.IDToken
+	return &client.AuthConfig, nil
+}
+
+// Login opens the browser to start an interactive oidc login and returns the config
+// with the new tokens.
+func Login(ctx context.Context, config *common.AuthConfig) (authConfig *common.AuthConfig, err error) {
+	if !config.Enable {
+		return nil, errors.New("oidc auth is disabled, enable it with --enable-auth or `auth.enable` in the config file")
+	}
+	enableAuth = true
+	client, err := newClient(ctx, *config)
+	if err != nil {
+		return nil, err
 	}
 	return client.newAuthReq(ctx)
 }
 
+// Logout returns a copy of config with all tokens and user information removed.
+func Logout(config *common.AuthConfig) *common.AuthConfig {
+	out := *config
+	out.IDToken = ""
+	out.RefreshToken = ""
+	out.Expiry = 0
+	out.Username = ""
+	idToken = ""
+	return &out
+}
+
 func newClient(ctx context.Context, config common.AuthConfig) (*client, error) {
-	httpClient := &http.Client{
-		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}, // ignore tls verify, because each has its own cert
+	transport, err := common.NewTransport(config.IssuerURL, config.TLSConfig)
+	if err != nil {
+		return nil, err
 	}
+	httpClient := &http.Client{Transport: transport}
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
 	provider, err := gooidc.NewProvider(ctx, config.IssuerURL)
 	if err != nil {
@@ -186,12 +218,12 @@ func (c *client) verifyIDToken(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("could not verify the ID token: %w", err)
 	}
-	oUser := make(map[string]interface{})
-	err = idToken.Claims(&oUser)
+	claims := Claims{}
+	err = idToken.Claims(&claims)
 	if err != nil {
 		return fmt.Errorf("could not parse the ID token: %w", err)
 	}
-	c.AuthConfig.Username = oUser["preferred_username"].(string)
+	c.AuthConfig.Username = claims.Username
 	return nil
 }
 
@@ -224,6 +256,10 @@ var idToken string
 var enableAuth bool
 
 func AddAuthHeader(req *http.Request) {
+	// requests signed by other means, such as object store uploads, keep their own credentials
+	if req.Header.Get("Authorization") != "" {
+		return
+	}
 	if enableAuth && idToken != "" {
 		req.Header.Add("Authorization", "Bearer "+idToken)
 	}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Claims is the part of ID token claims which bc-cli cares about.
+type Claims struct {
+	Username string   `json:"preferred_username"`
+	Email    string   `json:"email"`
+	Groups   []string `json:"groups"`
+	Expiry   int64    `json:"exp"`
+}
+
+// ExpiryTime returns the expiry of the ID token as time.Time
+func (c *Claims) ExpiryTime() time.Time {
+	return time.Unix(c.Expiry, 0)
+}
+
+// ParseClaims decodes the claims of a raw ID token.
+// Note: the signature is NOT verified here, the token should be verified by Auth before.
+func ParseClaims(rawIDToken string) (*Claims, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed ID token: expect 3 parts got %d", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("malformed ID token payload: %w", err)
+	}
+	claims := &Claims{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return nil, fmt.Errorf("could not parse the ID token: %w", err)
+	}
+	return claims, nil
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"preferred_username":"alice","email":"alice@example.com","groups":["bestchains","observability"],"exp":1683869103}`))
+	claims, err := ParseClaims("header." + payload + ".signature")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", claims.Username)
+	assert.Equal(t, "alice@example.com", claims.Email)
+	assert.Equal(t, []string{"bestchains", "observability"}, claims.Groups)
+	assert.Equal(t, int64(1683869103), claims.ExpiryTime().Unix())
+
+	// Malformed tokens
+	_, err = ParseClaims("not-a-token")
+	assert.Error(t, err)
+	_, err = ParseClaims("header.!!!.signature")
+	assert.Error(t, err)
+}
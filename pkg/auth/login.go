@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+// NewLoginCmd returns the command to login with oidc, the tokens are saved into config.
+func NewLoginCmd(option common.Options, config *common.AuthConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Login to Bestchains with oidc",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			authConfig, err := Login(cmd.Context(), config)
+			if err != nil {
+				return err
+			}
+			*config = *authConfig
+			fmt.Fprintf(option.Out, "logged in as %s\n", config.Username)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// NewLogoutCmd returns the command to remove the saved tokens from config.
+func NewLogoutCmd(option common.Options, config *common.AuthConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Logout from Bestchains",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if config.IDToken == "" {
+				fmt.Fprintln(option.Out, "not logged in")
+				return
+			}
+			username := config.Username
+			*config = *Logout(config)
+			fmt.Fprintf(option.Out, "%s logged out\n", username)
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/org"
+)
+
+// NewWhoamiCmd returns the command to display the current logged in user.
+func NewWhoamiCmd(option common.Options, config *common.AuthConfig) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Display the current logged in user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !config.Enable {
+				return fmt.Errorf("oidc auth is disabled, no user information available")
+			}
+			claims, err := ParseClaims(config.IDToken)
+			if err != nil {
+				return err
+			}
+			expiry := claims.ExpiryTime()
+			fmt.Fprintf(option.Out, "username: %s\n", claims.Username)
+			fmt.Fprintf(option.Out, "email: %s\n", claims.Email)
+			fmt.Fprintf(option.Out, "groups: %s\n", strings.Join(claims.Groups, ","))
+			fmt.Fprintf(option.Out, "expiry: %s (%s left)\n", expiry.Format(time.RFC3339), time.Until(expiry).Round(time.Second))
+
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				return err
+			}
+			orgs, err := org.ListUserOrganizations(cli, claims.Username)
+			if err != nil {
+				fmt.Fprintf(option.ErrOut, "failed to list organizations: %s\n", err)
+				orgs = nil
+			}
+			fmt.Fprintf(option.Out, "organizations: %s\n", strings.Join(orgs, ","))
+			return nil
+		},
+	}
+	return cmd
+}
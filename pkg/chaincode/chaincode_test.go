@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+)
+
+var proposalGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Proposal}
+
+func newFakeClient(objs ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		chaincodeGVR:      "ChaincodeList",
+		chaincodeBuildGVR: "ChaincodeBuildList",
+	}, objs...)
+}
+
+func newBuild(name string, version string, statusType string) *unstructured.Unstructured {
+	build := chaincodebuild.NewChaincodeBuild(chaincodebuild.CreateOptions{Name: name, Network: "net1", ID: "basic", Version: version})
+	_ = unstructured.SetNestedField(build.Object, statusType, "status", "type")
+	return build
+}
+
+func TestUpgrade(t *testing.T) {
+	cc := NewChaincode(DeployOptions{Name: "ch1-basic", Channel: "ch1", ID: "basic", Version: "v1", Build: "basic-v1", EndorsePolicy: "ep1"})
+	assert.Equal(t, "ch1-basic", DefaultName("ch1", "basic"))
+	assert.Equal(t, int64(1), Sequence(cc))
+	assert.False(t, Committed(cc))
+
+	_, err := Upgrade(cc, "v1", "basic-v1")
+	assert.Error(t, err)
+
+	upgraded, err := Upgrade(cc, "v2", "basic-v2")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), Sequence(upgraded))
+	assert.Equal(t, "v2", upgraded.GetLabels()["bestchains.chaincode.version"])
+	ep, _, _ := unstructured.NestedString(upgraded.Object, "spec", "endorsePolicyRef", "name")
+	assert.Equal(t, "ep1", ep)
+	assert.Equal(t, "v1", cc.GetLabels()["bestchains.chaincode.version"], "the original chaincode is not changed")
+
+	// the previous sequence stays committed until the operator handles the new one
+	assert.NoError(t, unstructured.SetNestedField(upgraded.Object, PhaseCommitted, "status", "phase"))
+	assert.False(t, Committed(upgraded), "status.sequence is not reported yet")
+	assert.NoError(t, unstructured.SetNestedField(upgraded.Object, int64(1), "status", "sequence"))
+	assert.False(t, Committed(upgraded))
+	assert.NoError(t, unstructured.SetNestedField(upgraded.Object, int64(2), "status", "sequence"))
+	assert.True(t, Committed(upgraded))
+}
+
+func TestFindBuild(t *testing.T) {
+	cli := newFakeClient(
+		newBuild("basic-v1", "v1", chaincodebuild.StatusPipelineSucceeded),
+		newBuild("basic-v2", "v2", chaincodebuild.StatusPipelineFailed),
+		newBuild("basic-v3-a", "v3", chaincodebuild.StatusPipelineSucceeded),
+		newBuild("basic-v3-b", "v3", chaincodebuild.StatusPipelineSucceeded),
+	)
+	name, err := findBuild(context.Background(), cli, "net1", "basic", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, "basic-v1", name)
+
+	_, err = findBuild(context.Background(), cli, "net1", "basic", "v2")
+	assert.ErrorContains(t, err, "no succeeded chaincodebuild")
+	_, err = findBuild(context.Background(), cli, "net1", "basic", "v3")
+	assert.ErrorContains(t, err, "--build")
+
+	_, err = getBuild(context.Background(), cli, "basic-v1", "net2")
+	assert.ErrorContains(t, err, "network")
+	_, err = getBuild(context.Background(), cli, "basic-v2", "net1")
+	assert.ErrorContains(t, err, chaincodebuild.StatusPipelineFailed)
+
+	// the build of an upgrade must build the new version of the same chaincode
+	cc := NewChaincode(DeployOptions{Name: "ch1-basic", Channel: "ch1", ID: "basic", Version: "v1"})
+	assert.NoError(t, checkUpgradeBuild(newBuild("basic-v3-a", "v3", chaincodebuild.StatusPipelineSucceeded), cc, "v3"))
+	assert.ErrorContains(t, checkUpgradeBuild(newBuild("basic-v3-a", "v3", chaincodebuild.StatusPipelineSucceeded), cc, "v4"), "version v3")
+	other := chaincodebuild.NewChaincodeBuild(chaincodebuild.CreateOptions{Name: "token-v3", Network: "net1", ID: "token", Version: "v3"})
+	assert.ErrorContains(t, checkUpgradeBuild(other, cc, "v3"), "builds chaincode token, not basic")
+}
+
+func TestWaitForCommit(t *testing.T) {
+	common.WaitInterval = 10 * time.Millisecond
+	newProposal := func(votes ...interface{}) *unstructured.Unstructured {
+		p := proposal.NewProposal(proposal.Options{Name: "deploy", Policy: proposal.PolicyAll}, proposal.SourceDeployChaincode, map[string]interface{}{
+			"chaincode": "ch1-basic",
+		})
+		_ = unstructured.SetNestedSlice(p.Object, votes, "status", "votes")
+		return p
+	}
+	cc := NewChaincode(DeployOptions{Name: "ch1-basic", Channel: "ch1", ID: "basic", Version: "v1"})
+	cli := newFakeClient(cc, newProposal(
+		map[string]interface{}{"organizationName": "org1", "decision": true},
+		map[string]interface{}{"organizationName": "org2"},
+	))
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_, _ = cli.Resource(proposalGVR).Update(context.Background(), newProposal(
+			map[string]interface{}{"organizationName": "org1", "decision": true},
+			map[string]interface{}{"organizationName": "org2", "decision": true},
+		), v1.UpdateOptions{})
+		committed := cc.DeepCopy()
+		_ = unstructured.SetNestedField(committed.Object, PhaseCommitted, "status", "phase")
+		_, _ = cli.Resource(chaincodeGVR).Update(context.Background(), committed, v1.UpdateOptions{})
+	}()
+	out := &bytes.Buffer{}
+	assert.NoError(t, WaitForCommit(context.Background(), cli, out, "ch1-basic", "deploy", time.Second))
+	assert.Contains(t, out.String(), "  org1: approved\n  org2: pending\nchaincode/ch1-basic Pending\n")
+	assert.Contains(t, out.String(), "  org2: approved\n")
+	assert.Contains(t, out.String(), "chaincode/ch1-basic Committed\n")
+
+	_, err := cli.Resource(proposalGVR).Update(context.Background(), newProposal(
+		map[string]interface{}{"organizationName": "org1", "decision": true},
+		map[string]interface{}{"organizationName": "org2", "decision": false, "description": "not audited"},
+	), v1.UpdateOptions{})
+	assert.NoError(t, err)
+	out.Reset()
+	err = WaitForCommit(context.Background(), cli, out, "ch1-basic", "deploy", time.Second)
+	assert.ErrorContains(t, err, "rejected")
+	assert.Contains(t, out.String(), "org2: rejected: not audited")
+}
+
+func TestUpgradeWithProposal(t *testing.T) {
+	ch := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "ch1"},
+		"spec": map[string]interface{}{
+			"members": []interface{}{map[string]interface{}{"name": "org1"}, map[string]interface{}{"name": "org2"}},
+		},
+	}}
+	cc := NewChaincode(DeployOptions{Name: "ch1-basic", Channel: "ch1", ID: "basic", Version: "v1", Build: "basic-v1", EndorsePolicy: "ep1"})
+	_ = unstructured.SetNestedField(cc.Object, PhaseCommitted, "status", "phase")
+	cli := newFakeClient(cc)
+
+	// the initiator is checked before the chaincode is touched
+	_, err := newChaincodeProposal(context.Background(), cli, proposal.Options{Initiator: "org3"}, proposal.SourceUpgradeChaincode, ch, "ch1-basic", "basic-v2")
+	assert.ErrorContains(t, err, "not a member")
+	p, err := newChaincodeProposal(context.Background(), cli, proposal.Options{Initiator: "org1"}, proposal.SourceUpgradeChaincode, ch, "ch1-basic", "basic-v2")
+	assert.NoError(t, err)
+	policy, _, _ := unstructured.NestedString(p.Object, "spec", "policy")
+	assert.Equal(t, proposal.PolicyAll, policy)
+
+	// the chaincode is restored when its proposal can not be created
+	cli.PrependReactor("create", "proposals", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("denied")
+	})
+	upgraded, err := Upgrade(cc, "v2", "basic-v2")
+	assert.NoError(t, err)
+	out := &bytes.Buffer{}
+	err = upgradeWithProposal(context.Background(), cli, out, cc, upgraded, p)
+	assert.ErrorContains(t, err, "denied")
+	assert.Equal(t, "chaincode/ch1-basic upgraded to version v2, sequence 2\nchaincode/ch1-basic restored to version v1, sequence 1\n", out.String())
+	restored, err := cli.Resource(chaincodeGVR).Get(context.Background(), "ch1-basic", v1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), Sequence(restored))
+	assert.Equal(t, "v1", restored.GetLabels()["bestchains.chaincode.version"])
+	assert.True(t, Committed(restored))
+}
@@ -0,0 +1,354 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// Phases of a chaincode, the operator installs the chaincode on the peers of the channel,
+// approves it for each organization once its vote approves, then commits it to the channel.
+const (
+	PhasePending   = "Pending"
+	PhaseInstalled = "Installed"
+	PhaseApproved  = "Approved"
+	PhaseCommitted = "Committed"
+	PhaseError     = "Error"
+)
+
+var (
+	chaincodeGVR      = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Chaincode}
+	chaincodeBuildGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.ChaincodeBuild}
+	channelGVR        = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Channel}
+	networkGVR        = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.NetworkResource}
+	endorsePolicyGVR  = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.EndorsePolicy}
+)
+
+// DefaultName returns a valid resource name of chaincode id on channel, such as channel1-basic.
+// The name does not contain the version so that upgrades keep the same chaincode.
+func DefaultName(channel string, id string) string {
+	return utils.ResourceName(channel, id)
+}
+
+// DeployOptions are options to deploy a chaincode built by a chaincodebuild to a channel
+type DeployOptions struct {
+	Name          string
+	Channel       string
+	ID            string
+	Version       string
+	Build         string
+	EndorsePolicy string
+	Initiator     string
+}
+
+// NewChaincode builds the Chaincode CR of sequence 1
+func NewChaincode(options DeployOptions) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "Chaincode",
+		"metadata": map[string]interface{}{
+			"name": options.Name,
+			"labels": map[string]interface{}{
+				"bestchains.chaincode.channel": options.Channel,
+				"bestchains.chaincode.id":      options.ID,
+				"bestchains.chaincode.version": options.Version,
+			},
+		},
+		"spec": map[string]interface{}{
+			"license": map[string]interface{}{
+				"accept": true,
+			},
+			"channel":         options.Channel,
+			"id":              options.ID,
+			"version":         options.Version,
+			"sequence":        int64(1),
+			"initiator":       options.Initiator,
+			"externalBuilder": options.Build,
+			"endorsePolicyRef": map[string]interface{}{
+				"name": options.EndorsePolicy,
+			},
+		},
+	}}
+}
+
+// Sequence returns spec.sequence of chaincode, 1 if not set
+func Sequence(chaincode *unstructured.Unstructured) int64 {
+	sequence, found, _ := unstructured.NestedInt64(chaincode.Object, "spec", "sequence")
+	if !found || sequence < 1 {
+		return 1
+	}
+	return sequence
+}
+
+// Phase returns status.phase of chaincode, Pending if the operator has not handled it yet
+func Phase(chaincode *unstructured.Unstructured) string {
+	phase, _, _ := unstructured.NestedString(chaincode.Object, "status", "phase")
+	if phase == "" {
+		return PhasePending
+	}
+	return phase
+}
+
+// Committed reports whether the current sequence of chaincode has been committed,
+// status.sequence is the last sequence handled by the operator. Without status.sequence only
+// the first sequence counts as committed, as the phase may still be the one of the previous sequence.
+func Committed(chaincode *unstructured.Unstructured) bool {
+	if Phase(chaincode) != PhaseCommitted {
+		return false
+	}
+	observed, found, _ := unstructured.NestedInt64(chaincode.Object, "status", "sequence")
+	if !found {
+		return Sequence(chaincode) == 1
+	}
+	return observed >= Sequence(chaincode)
+}
+
+// getBuild returns the chaincodebuild name which must have succeeded in network
+func getBuild(ctx context.Context, cli dynamic.Interface, name string, network string) (*unstructured.Unstructured, error) {
+	build, err := cli.Resource(chaincodeBuildGVR).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if n := utils.GetNestedString(build.Object, "spec", "network"); n != network {
+		return nil, fmt.Errorf("chaincodebuild %s belongs to network %s, not network %s of the channel", name, n, network)
+	}
+	if t := common.StatusType(build); t != chaincodebuild.StatusPipelineSucceeded {
+		return nil, fmt.Errorf("chaincodebuild %s has not succeeded, current status is %s", name, t)
+	}
+	return build, nil
+}
+
+// checkEndorsePolicy returns an error if endorse policy name is not in channel
+func checkEndorsePolicy(ctx context.Context, cli dynamic.Interface, name string, channel string) error {
+	ep, err := cli.Resource(endorsePolicyGVR).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if ch := utils.GetNestedString(ep.Object, "spec", "channel"); ch != channel {
+		return fmt.Errorf("endorsepolicy %s belongs to channel %s, not channel %s", name, ch, channel)
+	}
+	return nil
+}
+
+// channelPolicy returns the proposal policy of channel, which is the policy of the federation of its network
+func channelPolicy(ctx context.Context, cli dynamic.Interface, ch *unstructured.Unstructured) string {
+	network, err := cli.Resource(networkGVR).Get(ctx, utils.GetNestedString(ch.Object, "spec", "network"), v1.GetOptions{})
+	if err != nil {
+		return proposal.PolicyAll
+	}
+	return proposal.FederationPolicy(ctx, cli, utils.GetNestedString(network.Object, "spec", "federation"))
+}
+
+// newChaincodeProposal returns the proposal for the members of channel ch to approve chaincode built by build,
+// it fails if the initiator is not a member of ch.
+func newChaincodeProposal(ctx context.Context, cli dynamic.Interface, options proposal.Options, sourceType string,
+	ch *unstructured.Unstructured, chaincode string, build string) (*unstructured.Unstructured, error) {
+	if options.Initiator == "" {
+		var err error
+		if options.Initiator, err = proposal.DefaultInitiator(ctx, cli); err != nil {
+			return nil, err
+		}
+	}
+	members := relation.Members(ch)
+	if !utils.ContainsString(members, options.Initiator) {
+		return nil, fmt.Errorf("initiator %s is not a member of channel %s", options.Initiator, ch.GetName())
+	}
+	options.Policy = channelPolicy(ctx, cli, ch)
+	specMembers := make([]interface{}, 0, len(members))
+	for _, m := range members {
+		specMembers = append(specMembers, m)
+	}
+	p := proposal.NewProposal(options, sourceType, map[string]interface{}{
+		"chaincode":       chaincode,
+		"externalBuilder": build,
+		"members":         specMembers,
+	})
+	return p, nil
+}
+
+// approvalStatus returns the approval of each organization in the votes of p, such as approved or pending
+func approvalStatus(p *unstructured.Unstructured) map[string]string {
+	status := make(map[string]string)
+	for _, v := range proposal.Votes(p) {
+		switch {
+		case v.Decision == nil:
+			status[v.Organization] = "pending"
+		case *v.Decision:
+			status[v.Organization] = "approved"
+		default:
+			status[v.Organization] = "rejected"
+			if v.Description != "" {
+				status[v.Organization] += ": " + v.Description
+			}
+		}
+	}
+	return status
+}
+
+// WaitForCommit prints the approval of each organization in proposal proposalName and the phase of chaincode name
+// whenever they change, until the chaincode is committed. It fails if the chaincode reports an error
+// or the proposal can not pass. timeout 0 means waiting until ctx is done.
+func WaitForCommit(ctx context.Context, cli dynamic.Interface, out io.Writer, name string, proposalName string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	approvals := map[string]string{}
+	phase := ""
+	err := wait.PollUntilContextCancel(ctx, common.WaitInterval, true, func(ctx context.Context) (bool, error) {
+		p, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Proposal}).
+			Get(ctx, proposalName, v1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		status := approvalStatus(p)
+		for _, org := range sortedKeys(status) {
+			if approvals[org] != status[org] {
+				fmt.Fprintf(out, "  %s: %s\n", org, status[org])
+			}
+		}
+		approvals = status
+		if proposal.Failed(p) {
+			return false, fmt.Errorf("proposal %s is rejected", proposalName)
+		}
+
+		chaincode, err := cli.Resource(chaincodeGVR).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		current := Phase(chaincode)
+		if current == PhaseCommitted && !Committed(chaincode) {
+			// the previous sequence is committed, the new one is not handled yet
+			current = PhasePending
+		}
+		if current != phase {
+			phase = current
+			fmt.Fprintf(out, "chaincode/%s %s\n", name, phase)
+		}
+		switch phase {
+		case PhaseCommitted:
+			return true, nil
+		case PhaseError:
+			reason, _, _ := unstructured.NestedString(chaincode.Object, "status", "reason")
+			message, _, _ := unstructured.NestedString(chaincode.Object, "status", "message")
+			return false, fmt.Errorf("chaincode %s failed: %s %s", name, reason, message)
+		}
+		return false, nil
+	})
+	if err != nil && wait.Interrupted(err) {
+		return fmt.Errorf("timed out waiting for chaincode %s to be committed, current phase is %s", name, phase)
+	}
+	return err
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func NewCCDeployCmd(option common.Options) *cobra.Command {
+	var (
+		options         DeployOptions
+		proposalOptions proposal.Options
+		watch           bool
+		timeout         time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "chaincode --build CCB --channel CHANNEL --endorse-policy EP [--name NAME] [--initiator ORG]",
+		Short: "Deploy a chaincode built by a chaincodebuild to a channel",
+		Long: `Deploy a chaincode built by a chaincodebuild to a channel.
+
+The chaincode is created with sequence 1 together with a proposal for the members of the channel to approve it.
+The operator installs the chaincode on the peers of the channel, approves it for each organization which votes
+to approve, and commits it once the proposal passes. The approval of each organization is printed until
+the chaincode is committed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			ch, err := cli.Resource(channelGVR).Get(cmd.Context(), options.Channel, v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			build, err := getBuild(cmd.Context(), cli, options.Build, utils.GetNestedString(ch.Object, "spec", "network"))
+			if err != nil {
+				return err
+			}
+			if err := checkEndorsePolicy(cmd.Context(), cli, options.EndorsePolicy, options.Channel); err != nil {
+				return err
+			}
+			options.ID = utils.GetNestedString(build.Object, "spec", "id")
+			options.Version = utils.GetNestedString(build.Object, "spec", "version")
+			if options.Name == "" {
+				options.Name = DefaultName(options.Channel, options.ID)
+			}
+			if proposalOptions.Initiator == "" {
+				if proposalOptions.Initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+					return err
+				}
+			}
+			options.Initiator = proposalOptions.Initiator
+
+			p, err := newChaincodeProposal(cmd.Context(), cli, proposalOptions, proposal.SourceDeployChaincode, ch, options.Name, options.Build)
+			if err != nil {
+				return err
+			}
+			if err := proposal.CreateWithProposal(cmd.Context(), cli, option.Out, chaincodeGVR, NewChaincode(options), p); err != nil || !watch {
+				return err
+			}
+			return WaitForCommit(cmd.Context(), cli, option.Out, options.Name, p.GetName(), timeout)
+		},
+	}
+
+	proposal.AddFlags(cmd, &proposalOptions)
+	cmd.Flags().StringVar(&options.Name, "name", "", "name of the chaincode, default to CHANNEL-ID")
+	cmd.Flags().StringVar(&options.Build, "build", "", "chaincodebuild which has built the chaincode")
+	cmd.Flags().StringVar(&options.Channel, "channel", "", "channel to deploy the chaincode")
+	cmd.Flags().StringVar(&options.EndorsePolicy, "endorse-policy", "", "endorsepolicy of the chaincode in the channel")
+	cmd.Flags().BoolVar(&watch, "wait", true, "print the approval of each organization until the chaincode is committed")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "the length of time to wait for the chaincode to be committed, zero means wait forever")
+	_ = cmd.MarkFlagRequired("build")
+	_ = cmd.MarkFlagRequired("channel")
+	_ = cmd.MarkFlagRequired("endorse-policy")
+	return cmd
+}
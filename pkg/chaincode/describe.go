@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+func describeDetails(w describe.PrefixWriter, chaincode *unstructured.Unstructured) {
+	spec := func(fields ...string) string {
+		if v := utils.GetNestedString(chaincode.Object, append([]string{"spec"}, fields...)...); v != "" {
+			return v
+		}
+		return "<none>"
+	}
+	w.Write(describe.LEVEL_0, "Name:\t%s\n", chaincode.GetName())
+	w.Write(describe.LEVEL_0, "Channel:\t%s\n", spec("channel"))
+	w.Write(describe.LEVEL_0, "ID:\t%s\n", spec("id"))
+	w.Write(describe.LEVEL_0, "Version:\t%s\n", spec("version"))
+	w.Write(describe.LEVEL_0, "Sequence:\t%d\n", Sequence(chaincode))
+	w.Write(describe.LEVEL_0, "Initiator:\t%s\n", spec("initiator"))
+	w.Write(describe.LEVEL_0, "Build:\t%s\n", spec("externalBuilder"))
+	w.Write(describe.LEVEL_0, "Endorse Policy:\t%s\n", spec("endorsePolicyRef", "name"))
+	relation.WriteCommon(w, chaincode)
+}
+
+func NewCCDescribeCmd(option common.Options) *cobra.Command {
+	return relation.NewDescribeCmd(option, relation.KindChaincode, &cobra.Command{
+		Use:     "chaincode NAME...",
+		Aliases: []string{"cc"},
+		Short:   "Show details of chaincodes with their channel, build, endorsepolicy and events",
+	}, describeDetails)
+}
@@ -17,30 +17,43 @@ limitations under the License.
 package chaincode
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/kubectl/pkg/cmd/get"
 
 	"github.com/bestchains/bc-cli/pkg/common"
-	"github.com/bestchains/bc-cli/pkg/utils"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/resource"
 )
 
+// Kind is the kind of chaincodes
+var Kind = resource.Kind{
+	Kind:    relation.KindChaincode,
+	Name:    "chaincode",
+	Aliases: []string{"cc"},
+	GVR:     schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Chaincode},
+	Columns: []resource.Column{
+		{Name: "ID", Value: resource.Field("spec", "id")},
+		{Name: "Version", Value: resource.Field("spec", "version")},
+		{Name: "Channel", Value: resource.Field("spec", "channel")},
+		{Name: "Phase", Value: func(obj *unstructured.Unstructured) interface{} { return Phase(obj) }},
+		{Name: "Sequence", Wide: true, Value: func(obj *unstructured.Unstructured) interface{} { return Sequence(obj) }},
+		{Name: "EndorsePolicy", Wide: true, Value: resource.Field("spec", "endorsePolicyRef", "name")},
+		{Name: "Initiator", Wide: true, Value: resource.Field("spec", "initiator")},
+	},
+}
+
 func NewCCGetCmd(option common.Options) *cobra.Command {
 	var (
 		channel string
 		id      string
 		version string
 	)
-	defaultPrintFlag := get.NewGetPrintFlags()
+	flags := resource.NewGetFlags()
 	cmd := &cobra.Command{
 		Use:   "chaincode [NAME]",
 		Short: "Get a list of the chaincode installed on a channel",
@@ -53,78 +66,30 @@ func NewCCGetCmd(option common.Options) *cobra.Command {
 				}
 			}
 		},
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
 			cli, err := common.GetDynamicClient()
 			if err != nil {
 				fmt.Fprintln(option.ErrOut, err)
-				return
+				return err
 			}
 
-			list := corev1.List{
-				TypeMeta: v1.TypeMeta{
-					Kind:       "List",
-					APIVersion: "v1",
-				},
-				ListMeta: v1.ListMeta{},
-			}
-			if len(args) == 0 {
-				var labels []string
-				for k, v := range map[string]string{
-					"channel": channel,
-					"id":      id,
-					"version": version,
-				} {
-					if v != "" {
-						labels = append(labels, fmt.Sprintf("bestchains.chaincode.%s=%s", k, v))
-					}
-				}
-				chaincodes, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Chaincode}).List(context.TODO(), v1.ListOptions{
-					LabelSelector: strings.Join(labels, ","),
-				})
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				for i := 0; i < len(chaincodes.Items); i++ {
-					list.Items = append(list.Items, runtime.RawExtension{Object: &chaincodes.Items[i]})
+			var labels []string
+			for k, v := range map[string]string{
+				"channel": channel,
+				"id":      id,
+				"version": version,
+			} {
+				if v != "" {
+					labels = append(labels, fmt.Sprintf("bestchains.chaincode.%s=%s", k, v))
 				}
-			} else {
-				for _, arg := range utils.RemoveDuplicateForStringSlice(args) {
-					chaincode, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Chaincode}).Get(context.TODO(), arg, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						continue
-					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: chaincode})
-				}
-			}
-			var obj runtime.Object
-			if len(list.Items) != 1 {
-				listData, err := json.Marshal(list)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, listData)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				obj = converted
-			} else {
-				obj = list.Items[0].Object
-			}
-
-			p, err := defaultPrintFlag.ToPrinter()
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
 			}
-			_ = p.PrintObj(obj, option.Out)
+			sort.Strings(labels)
+			return flags.Run(cmd.Context(), cli, option, Kind, resource.Options{Names: args, LabelSelector: strings.Join(labels, ",")})
 		},
 	}
 
-	defaultPrintFlag.AddFlags(cmd)
+	flags.AddFlags(cmd)
 	cmd.Flags().StringVar(&channel, "channel", "", "channel name")
 	cmd.Flags().StringVar(&id, "id", "", "chaincode id")
 	cmd.Flags().StringVar(&version, "version", "", "chaincode version")
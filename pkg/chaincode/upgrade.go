@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// findBuild returns the name of the only succeeded chaincodebuild of chaincode id and version in network
+func findBuild(ctx context.Context, cli dynamic.Interface, network string, id string, version string) (string, error) {
+	list, err := cli.Resource(chaincodeBuildGVR).List(ctx, v1.ListOptions{
+		LabelSelector: fmt.Sprintf("bestchains.chaincodebuild.network=%s,bestchains.chaincodebuild.id=%s,bestchains.chaincodebuild.version=%s", network, id, version),
+	})
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for _, item := range list.Items {
+		if common.StatusType(&item) == chaincodebuild.StatusPipelineSucceeded {
+			names = append(names, item.GetName())
+		}
+	}
+	switch len(names) {
+	case 0:
+		return "", fmt.Errorf("no succeeded chaincodebuild of chaincode %s version %s, build it with `bc-cli create ccb` first", id, version)
+	case 1:
+		return names[0], nil
+	}
+	return "", fmt.Errorf("chaincodebuilds %s all build chaincode %s version %s, choose one with --build", strings.Join(names, ", "), id, version)
+}
+
+// checkUpgradeBuild returns an error if build does not build version of the same chaincode id as chaincode
+func checkUpgradeBuild(build *unstructured.Unstructured, chaincode *unstructured.Unstructured, version string) error {
+	if id, want := utils.GetNestedString(build.Object, "spec", "id"), utils.GetNestedString(chaincode.Object, "spec", "id"); id != want {
+		return fmt.Errorf("chaincodebuild %s builds chaincode %s, not %s of chaincode %s", build.GetName(), id, want, chaincode.GetName())
+	}
+	if v := utils.GetNestedString(build.Object, "spec", "version"); v != version {
+		return fmt.Errorf("chaincodebuild %s builds version %s, not %s", build.GetName(), v, version)
+	}
+	return nil
+}
+
+// Upgrade sets version and chaincodebuild of chaincode and increases its sequence,
+// the other fields such as the endorsepolicy are kept.
+func Upgrade(chaincode *unstructured.Unstructured, version string, build string) (*unstructured.Unstructured, error) {
+	upgraded := chaincode.DeepCopy()
+	if current := utils.GetNestedString(chaincode.Object, "spec", "version"); current == version {
+		return nil, fmt.Errorf("chaincode %s is already at version %s", chaincode.GetName(), version)
+	}
+	if err := unstructured.SetNestedField(upgraded.Object, version, "spec", "version"); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(upgraded.Object, build, "spec", "externalBuilder"); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedField(upgraded.Object, Sequence(chaincode)+1, "spec", "sequence"); err != nil {
+		return nil, err
+	}
+	labels := upgraded.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels["bestchains.chaincode.version"] = version
+	upgraded.SetLabels(labels)
+	return upgraded, nil
+}
+
+// upgradeWithProposal updates chaincode to upgraded and then creates proposal p for it. The spec and labels
+// of chaincode are restored if p can not be created, otherwise the new sequence could never be approved.
+func upgradeWithProposal(ctx context.Context, cli dynamic.Interface, out io.Writer, chaincode *unstructured.Unstructured,
+	upgraded *unstructured.Unstructured, p *unstructured.Unstructured) error {
+	updated, err := cli.Resource(chaincodeGVR).Update(ctx, upgraded, v1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "chaincode/%s upgraded to version %s, sequence %d\n", updated.GetName(),
+		utils.GetNestedString(updated.Object, "spec", "version"), Sequence(updated))
+
+	if err := proposal.CreateAndReport(ctx, cli, out, p); err != nil {
+		restored := updated.DeepCopy()
+		restored.Object["spec"] = chaincode.DeepCopy().Object["spec"]
+		restored.SetLabels(chaincode.GetLabels())
+		if _, restoreErr := cli.Resource(chaincodeGVR).Update(ctx, restored, v1.UpdateOptions{}); restoreErr != nil {
+			return fmt.Errorf("create proposal: %w, and chaincode/%s is left at sequence %d without a proposal: %s", err, updated.GetName(), Sequence(updated), restoreErr)
+		}
+		fmt.Fprintf(out, "chaincode/%s restored to version %s, sequence %d\n", restored.GetName(),
+			utils.GetNestedString(restored.Object, "spec", "version"), Sequence(restored))
+		return fmt.Errorf("create proposal: %w", err)
+	}
+	return nil
+}
+
+func NewCCUpgradeCmd(option common.Options) *cobra.Command {
+	var (
+		version         string
+		build           string
+		proposalOptions proposal.Options
+		watch           bool
+		timeout         time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "chaincode NAME --version VERSION [--build CCB] [--initiator ORG]",
+		Short: "Upgrade a chaincode to a new version",
+		Long: `Upgrade a chaincode to a new version.
+
+The sequence of the chaincode is increased while its endorsepolicy is kept, and a proposal is created for
+the members of the channel to approve the new definition. The chaincodebuild defaults to the only succeeded
+build of the new version in the network of the channel.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			chaincode, err := cli.Resource(chaincodeGVR).Get(cmd.Context(), args[0], v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if !Committed(chaincode) {
+				return fmt.Errorf("chaincode %s is not committed yet, only committed chaincodes can be upgraded", chaincode.GetName())
+			}
+			ch, err := cli.Resource(channelGVR).Get(cmd.Context(), utils.GetNestedString(chaincode.Object, "spec", "channel"), v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			network := utils.GetNestedString(ch.Object, "spec", "network")
+			if build == "" {
+				if build, err = findBuild(cmd.Context(), cli, network, utils.GetNestedString(chaincode.Object, "spec", "id"), version); err != nil {
+					return err
+				}
+			}
+			buildObj, err := getBuild(cmd.Context(), cli, build, network)
+			if err != nil {
+				return err
+			}
+			if err := checkUpgradeBuild(buildObj, chaincode, version); err != nil {
+				return err
+			}
+
+			upgraded, err := Upgrade(chaincode, version, build)
+			if err != nil {
+				return err
+			}
+			p, err := newChaincodeProposal(cmd.Context(), cli, proposalOptions, proposal.SourceUpgradeChaincode, ch, upgraded.GetName(), build)
+			if err != nil {
+				return err
+			}
+			if err := upgradeWithProposal(cmd.Context(), cli, option.Out, chaincode, upgraded, p); err != nil || !watch {
+				return err
+			}
+			return WaitForCommit(cmd.Context(), cli, option.Out, upgraded.GetName(), p.GetName(), timeout)
+		},
+	}
+
+	proposal.AddFlags(cmd, &proposalOptions)
+	cmd.Flags().StringVar(&version, "version", "", "new version of the chaincode")
+	cmd.Flags().StringVar(&build, "build", "", "chaincodebuild of the new version, default to the only succeeded one")
+	cmd.Flags().BoolVar(&watch, "wait", true, "print the approval of each organization until the chaincode is committed")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "the length of time to wait for the chaincode to be committed, zero means wait forever")
+	_ = cmd.MarkFlagRequired("version")
+	return cmd
+}
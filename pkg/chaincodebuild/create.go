@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincodebuild
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/objectstore"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// Status types of a chaincodebuild
+const (
+	StatusPipelineRunning   = "PipelineRunning"
+	StatusPipelineSucceeded = "PipelineRunSucceeded"
+	StatusPipelineFailed    = "PipelineRunFailed"
+)
+
+var chaincodeBuildGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.ChaincodeBuild}
+
+// DefaultName returns a valid resource name from id and version of a chaincodebuild, such as basic-v1-0
+func DefaultName(id string, version string) string {
+	return utils.ResourceName(id, version)
+}
+
+// CreateOptions are options to build a chaincode from the source uploaded to the object store
+type CreateOptions struct {
+	Name      string
+	Network   string
+	ID        string
+	Version   string
+	Language  string
+	Initiator string
+	Bucket    string
+	Object    string
+}
+
+// NewChaincodeBuild builds the ChaincodeBuild CR
+func NewChaincodeBuild(options CreateOptions) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "ChaincodeBuild",
+		"metadata": map[string]interface{}{
+			"name": options.Name,
+			"labels": map[string]interface{}{
+				"bestchains.chaincodebuild.id":      options.ID,
+				"bestchains.chaincodebuild.version": options.Version,
+				"bestchains.chaincodebuild.network": options.Network,
+			},
+		},
+		"spec": map[string]interface{}{
+			"license": map[string]interface{}{
+				"accept": true,
+			},
+			"id":        options.ID,
+			"version":   options.Version,
+			"network":   options.Network,
+			"initiator": options.Initiator,
+			"pipelineRunSpec": map[string]interface{}{
+				"language": options.Language,
+				"minio": map[string]interface{}{
+					"bucket": options.Bucket,
+					"object": options.Object,
+				},
+			},
+		},
+	}}
+}
+
+// WaitForBuild polls the chaincodebuild name and prints its status whenever it changes,
+// until the pipeline succeeds or fails. timeout 0 means waiting until ctx is done.
+func WaitForBuild(ctx context.Context, cli dynamic.Interface, out io.Writer, name string, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	last := ""
+	err := wait.PollUntilContextCancel(ctx, common.WaitInterval, true, func(ctx context.Context) (bool, error) {
+		obj, err := cli.Resource(chaincodeBuildGVR).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		statusType := common.StatusType(obj)
+		reason, _, _ := unstructured.NestedString(obj.Object, "status", "reason")
+		message, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+		current := strings.TrimSpace(strings.Join([]string{statusType, reason, message}, " "))
+		if statusType != "" && current != last {
+			fmt.Fprintf(out, "chaincodebuild/%s %s\n", name, current)
+			last = current
+		}
+		switch statusType {
+		case StatusPipelineSucceeded:
+			return true, nil
+		case StatusPipelineFailed, common.StatusError:
+			return false, fmt.Errorf("chaincodebuild %s failed: %s %s", name, reason, message)
+		}
+		return false, nil
+	})
+	if err != nil && wait.Interrupted(err) {
+		if last == "" {
+			last = "<none>"
+		}
+		return fmt.Errorf("timed out waiting for chaincodebuild %s, current status is %s", name, last)
+	}
+	return err
+}
+
+func NewCCBCreateCmd(option common.Options) *cobra.Command {
+	var (
+		options CreateOptions
+		source  string
+		store   common.ObjectStore
+		watch   bool
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "ccb --network NETWORK --id ID --version VERSION [--lang go] --source DIR",
+		Short: "Build a chaincode from a local source directory",
+		Long: `Build a chaincode from a local source directory.
+
+The directory is packaged as a tar.gz, skipping the files matched by the patterns in its .bcignore,
+uploaded to the object store in saas.objectstore of the config file, then the chaincodebuild is created
+and its status is printed until the build succeeds or fails.
+A file:// URL of the object store, such as file:///tmp/objects, writes the package into a local directory.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if options.Language != "go" {
+				return fmt.Errorf("unsupported language %q, only go is supported", options.Language)
+			}
+			info, err := os.Stat(source)
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() {
+				return fmt.Errorf("source %s is not a directory", source)
+			}
+			cfg := common.ObjectStore{}
+			_ = viper.UnmarshalKey("saas.objectstore", &cfg)
+			if store.Server != "" {
+				cfg.Server = store.Server
+			}
+			if store.Bucket != "" {
+				cfg.Bucket = store.Bucket
+			}
+			if cfg.Server == "" || cfg.Bucket == "" {
+				return fmt.Errorf("object store is not configured, set saas.objectstore in the config file or use --object-store and --bucket")
+			}
+			uploader, err := objectstore.NewUploader(cfg)
+			if err != nil {
+				return err
+			}
+
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if options.Initiator == "" {
+				if options.Initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+					return err
+				}
+			}
+			if options.Name == "" {
+				options.Name = DefaultName(options.ID, options.Version)
+			}
+
+			data, files, err := Package(source)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no files to upload in %s", source)
+			}
+			options.Bucket = cfg.Bucket
+			options.Object = fmt.Sprintf("%s/%s.tar.gz", options.ID, options.Version)
+			if err := uploader.Upload(cmd.Context(), options.Bucket, options.Object, data); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fmt.Fprintf(option.Out, "uploaded %d files (%d bytes) to %s/%s\n", len(files), len(data), options.Bucket, options.Object)
+
+			if _, err := cli.Resource(chaincodeBuildGVR).Create(cmd.Context(), NewChaincodeBuild(options), v1.CreateOptions{}); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fmt.Fprintf(option.Out, "chaincodebuild/%s created\n", options.Name)
+			if !watch {
+				return nil
+			}
+			return WaitForBuild(cmd.Context(), cli, option.Out, options.Name, timeout)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Name, "name", "", "name of the chaincodebuild, default to ID-VERSION")
+	cmd.Flags().StringVar(&options.Network, "network", "", "network to deploy the chaincode")
+	cmd.Flags().StringVar(&options.ID, "id", "", "chaincode id")
+	cmd.Flags().StringVar(&options.Version, "version", "", "chaincode version")
+	cmd.Flags().StringVar(&options.Language, "lang", "go", "language of the chaincode")
+	cmd.Flags().StringVar(&options.Initiator, "initiator", "", "organization which builds the chaincode, default to the only organization the user administers")
+	cmd.Flags().StringVar(&source, "source", ".", "source directory of the chaincode")
+	cmd.Flags().StringVar(&store.Server, "object-store", "", "url of the object store, overrides saas.objectstore.server in the config file")
+	cmd.Flags().StringVar(&store.Bucket, "bucket", "", "bucket to upload the source to, overrides saas.objectstore.bucket in the config file")
+	cmd.Flags().BoolVar(&watch, "wait", true, "print the build status until the build succeeds or fails")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "the length of time to wait for the build, zero means wait forever")
+	_ = cmd.MarkFlagRequired("network")
+	_ = cmd.MarkFlagRequired("id")
+	_ = cmd.MarkFlagRequired("version")
+	return cmd
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincodebuild
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+func TestDefaultName(t *testing.T) {
+	assert.Equal(t, "basic-v1-0", DefaultName("basic", "v1.0"))
+	assert.Equal(t, "my-cc-1-0-0", DefaultName("My_CC", "1.0.0"))
+}
+
+func TestNewChaincodeBuild(t *testing.T) {
+	ccb := NewChaincodeBuild(CreateOptions{Name: "basic-v1", Network: "net1", ID: "basic", Version: "v1", Language: "go",
+		Initiator: "org1", Bucket: "bestchains", Object: "basic/v1.tar.gz"})
+	assert.Equal(t, "ChaincodeBuild", ccb.GetKind())
+	assert.Equal(t, "net1", ccb.GetLabels()["bestchains.chaincodebuild.network"])
+	object, _, _ := unstructured.NestedString(ccb.Object, "spec", "pipelineRunSpec", "minio", "object")
+	assert.Equal(t, "basic/v1.tar.gz", object)
+	initiator, _, _ := unstructured.NestedString(ccb.Object, "spec", "initiator")
+	assert.Equal(t, "org1", initiator)
+}
+
+func TestWaitForBuild(t *testing.T) {
+	common.WaitInterval = 10 * time.Millisecond
+	withStatus := func(name, statusType string) *unstructured.Unstructured {
+		ccb := NewChaincodeBuild(CreateOptions{Name: name, ID: "basic", Version: "v1"})
+		_ = unstructured.SetNestedField(ccb.Object, statusType, "status", "type")
+		return ccb
+	}
+	cli := fake.NewSimpleDynamicClient(runtime.NewScheme(), withStatus("ok", StatusPipelineRunning), withStatus("failed", StatusPipelineFailed))
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_, _ = cli.Resource(chaincodeBuildGVR).Update(context.Background(), withStatus("ok", StatusPipelineSucceeded), v1.UpdateOptions{})
+	}()
+	out := &bytes.Buffer{}
+	assert.NoError(t, WaitForBuild(context.Background(), cli, out, "ok", time.Second))
+	assert.Equal(t, "chaincodebuild/ok PipelineRunning\nchaincodebuild/ok PipelineRunSucceeded\n", out.String())
+
+	assert.ErrorContains(t, WaitForBuild(context.Background(), cli, &bytes.Buffer{}, "failed", time.Second), "failed")
+}
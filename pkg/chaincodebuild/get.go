@@ -17,23 +17,35 @@ limitations under the License.
 package chaincodebuild
 
 import (
-	"context"
-	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/kubectl/pkg/cmd/get"
 
 	"github.com/bestchains/bc-cli/pkg/common"
-	"github.com/bestchains/bc-cli/pkg/utils"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/resource"
 )
 
+// Kind is the kind of chaincodebuilds
+var Kind = resource.Kind{
+	Kind:    relation.KindChaincodeBuild,
+	Name:    "chaincodebuild",
+	Aliases: []string{"ccb"},
+	GVR:     schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.ChaincodeBuild},
+	Columns: []resource.Column{
+		{Name: "ID", Value: resource.Field("spec", "id")},
+		{Name: "Version", Value: resource.Field("spec", "version")},
+		{Name: "Network", Value: resource.Field("spec", "network")},
+		{Name: "Status", Value: func(obj *unstructured.Unstructured) interface{} { return resource.OrNone(common.StatusType(obj)) }},
+		{Name: "Language", Wide: true, Value: resource.Field("spec", "pipelineRunSpec", "language")},
+		{Name: "Initiator", Wide: true, Value: resource.Field("spec", "initiator")},
+	},
+}
+
 func NewCCBGetCmd(option common.Options) *cobra.Command {
 	var (
 		network string
@@ -41,7 +53,7 @@ func NewCCBGetCmd(option common.Options) *cobra.Command {
 		version string
 	)
 
-	defaultPrintFlag := get.NewGetPrintFlags()
+	flags := resource.NewGetFlags()
 	cmd := &cobra.Command{
 		Use:   "ccb [NAME]",
 		Short: "Get the list of chaincodebuild created under a network",
@@ -54,79 +66,30 @@ func NewCCBGetCmd(option common.Options) *cobra.Command {
 				}
 			}
 		},
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
 			cli, err := common.GetDynamicClient()
 			if err != nil {
 				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-
-			list := corev1.List{
-				TypeMeta: v1.TypeMeta{
-					Kind:       "List",
-					APIVersion: "v1",
-				},
-				ListMeta: v1.ListMeta{},
-			}
-			var obj runtime.Object
-			if len(args) == 0 {
-				var labels []string
-				for k, v := range map[string]string{
-					"id":      id,
-					"version": version,
-					"network": network,
-				} {
-					if v != "" {
-						labels = append(labels, fmt.Sprintf("bestchains.chaincodebuild.%s=%s", k, v))
-					}
-				}
-				chaincodeBuilds, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.ChaincodeBuild}).List(context.TODO(), v1.ListOptions{
-					LabelSelector: strings.Join(labels, ","),
-				})
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				for i := 0; i < len(chaincodeBuilds.Items); i++ {
-					list.Items = append(list.Items, runtime.RawExtension{Object: &chaincodeBuilds.Items[i]})
-				}
-			} else {
-				for _, arg := range utils.RemoveDuplicateForStringSlice(args) {
-					chaincodeBuild, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.ChaincodeBuild}).Get(context.TODO(), arg, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						return
-					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: chaincodeBuild})
-				}
+				return err
 			}
 
-			if len(list.Items) != 1 {
-				listData, err := json.Marshal(list)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, listData)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
+			var labels []string
+			for k, v := range map[string]string{
+				"id":      id,
+				"version": version,
+				"network": network,
+			} {
+				if v != "" {
+					labels = append(labels, fmt.Sprintf("bestchains.chaincodebuild.%s=%s", k, v))
 				}
-				obj = converted
-			} else {
-				obj = list.Items[0].Object
-			}
-
-			p, err := defaultPrintFlag.ToPrinter()
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
 			}
-			_ = p.PrintObj(obj, option.Out)
+			sort.Strings(labels)
+			return flags.Run(cmd.Context(), cli, option, Kind, resource.Options{Names: args, LabelSelector: strings.Join(labels, ",")})
 		},
 	}
 
-	defaultPrintFlag.AddFlags(cmd)
+	flags.AddFlags(cmd)
 	cmd.Flags().StringVar(&network, "network", "", "choose a blockchain network")
 	cmd.Flags().StringVar(&id, "id", "", "chaincodeBuild id")
 	cmd.Flags().StringVar(&version, "version", "", "chaincodeBuild version")
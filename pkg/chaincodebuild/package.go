@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincodebuild
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile lists the files of a chaincode source directory which are not uploaded
+const IgnoreFile = ".bcignore"
+
+// defaultIgnores are never uploaded
+var defaultIgnores = []string{".git/"}
+
+// ignorePattern is a pattern in .bcignore, which is a subset of .gitignore:
+// a pattern with a trailing slash only matches directories, a pattern containing
+// a slash is matched against the path relative to the source directory,
+// otherwise it is matched against the base name of each file and directory.
+type ignorePattern struct {
+	pattern string
+	dirOnly bool
+	rooted  bool
+}
+
+func (p ignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.rooted {
+		ok, _ := path.Match(p.pattern, rel)
+		return ok
+	}
+	ok, _ := path.Match(p.pattern, path.Base(rel))
+	return ok
+}
+
+func parseIgnorePatterns(r io.Reader) ([]ignorePattern, error) {
+	var patterns []ignorePattern
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			p.rooted = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		p.pattern = line
+		patterns = append(patterns, p)
+	}
+	return patterns, scanner.Err()
+}
+
+// loadIgnorePatterns returns the default patterns and those in the .bcignore of dir if it exists
+func loadIgnorePatterns(dir string) ([]ignorePattern, error) {
+	patterns, _ := parseIgnorePatterns(strings.NewReader(strings.Join(defaultIgnores, "\n")))
+	f, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if os.IsNotExist(err) {
+		return patterns, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	custom, err := parseIgnorePatterns(f)
+	if err != nil {
+		return nil, err
+	}
+	return append(patterns, custom...), nil
+}
+
+// Package archives the source directory dir as a tar.gz, skipping files matched by .bcignore.
+// Paths in the archive are relative to dir and the names of the archived files are returned.
+func Package(dir string) ([]byte, []string, error) {
+	patterns, err := loadIgnorePatterns(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		buf   bytes.Buffer
+		files []string
+	)
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			if pattern.match(rel, info.IsDir()) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			// symlinks and devices can not be built remotely
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(tw, f); err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, nil, err
+	}
+	return buf.Bytes(), files, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaincodebuild
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackage(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":                    "module cc",
+		"main.go":                   "package main",
+		"main_test.go":              "package main",
+		"vendor/lib/lib.go":         "package lib",
+		"build/out.bin":             "bin",
+		"docs/build/index.md":       "doc",
+		".git/HEAD":                 "ref",
+		"testdata/fixture.json":     "{}",
+		"internal/testdata/a.json":  "{}",
+		IgnoreFile:                  "# comments and blank lines are skipped\n\n*_test.go\n/build/\ntestdata/\n",
+		"internal/chaincode/cc.go":  "package chaincode",
+		"internal/chaincode/cc.txt": "text",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	data, files, err := Package(dir)
+	assert.NoError(t, err)
+	expected := []string{
+		IgnoreFile,
+		"docs/build/index.md",
+		"go.mod",
+		"internal/chaincode/cc.go",
+		"internal/chaincode/cc.txt",
+		"main.go",
+		"vendor/lib/lib.go",
+	}
+	sort.Strings(files)
+	assert.Equal(t, expected, files)
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gr)
+	var archived []string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if header.Typeflag == tar.TypeReg {
+			archived = append(archived, header.Name)
+		}
+	}
+	sort.Strings(archived)
+	assert.Equal(t, expected, archived)
+}
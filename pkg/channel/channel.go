@@ -0,0 +1,213 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// NewChannelCmd returns the command to manage peers and members of channels
+func NewChannelCmd(option common.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "channel",
+		Short: "Manage peers and members of channels",
+	}
+	cmd.AddCommand(newJoinCmd(option))
+	cmd.AddCommand(newLeaveCmd(option))
+	cmd.AddCommand(newAddMemberCmd(option))
+	return cmd
+}
+
+// JoinPeers adds peers to spec.peers of channel, peers already in the channel are ignored
+func JoinPeers(ctx context.Context, cli dynamic.Interface, channel *unstructured.Unstructured, peers []Peer) (*unstructured.Unstructured, error) {
+	if err := checkPeers(peers, relation.Members(channel)); err != nil {
+		return nil, err
+	}
+	current := Peers(channel)
+	for _, p := range peers {
+		if !containsPeer(current, p) {
+			current = append(current, p)
+		}
+	}
+	return patchPeers(ctx, cli, channel.GetName(), current)
+}
+
+// LeavePeers removes peers from spec.peers of channel, an error is returned if any peer is not in the channel
+func LeavePeers(ctx context.Context, cli dynamic.Interface, channel *unstructured.Unstructured, peers []Peer) (*unstructured.Unstructured, error) {
+	current := Peers(channel)
+	for _, p := range peers {
+		if !containsPeer(current, p) {
+			return nil, fmt.Errorf("peer %s is not in channel %s", p, channel.GetName())
+		}
+	}
+	remain := make([]Peer, 0, len(current))
+	for _, p := range current {
+		if !containsPeer(peers, p) {
+			remain = append(remain, p)
+		}
+	}
+	return patchPeers(ctx, cli, channel.GetName(), remain)
+}
+
+func patchPeers(ctx context.Context, cli dynamic.Interface, name string, peers []Peer) (*unstructured.Unstructured, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"peers": peersToSpec(peers),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cli.Resource(channelGVR).Patch(ctx, name, types.MergePatchType, patch, v1.PatchOptions{})
+}
+
+func containsPeer(peers []Peer, peer Peer) bool {
+	for _, p := range peers {
+		if p == peer {
+			return true
+		}
+	}
+	return false
+}
+
+// peersCmd builds the join and leave commands which change the peers of a channel by update
+func peersCmd(option common.Options, cmd *cobra.Command, verb string, update func(context.Context, dynamic.Interface, *unstructured.Unstructured, []Peer) (*unstructured.Unstructured, error)) *cobra.Command {
+	var peers []string
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		parsedPeers, err := ParsePeers(peers)
+		if err != nil {
+			return err
+		}
+		if len(parsedPeers) == 0 {
+			return fmt.Errorf("no peer provided")
+		}
+		cli, err := common.GetDynamicClient()
+		if err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		channel, err := cli.Resource(channelGVR).Get(cmd.Context(), args[0], v1.GetOptions{})
+		if err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		if _, err := update(cmd.Context(), cli, channel, parsedPeers); err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		for _, p := range parsedPeers {
+			fmt.Fprintf(option.Out, "peer %s %s channel/%s\n", p, verb, channel.GetName())
+		}
+		return nil
+	}
+	cmd.Flags().StringSliceVar(&peers, "peer", nil, "peers such as org1:peer0")
+	_ = cmd.MarkFlagRequired("peer")
+	return cmd
+}
+
+func newJoinCmd(option common.Options) *cobra.Command {
+	return peersCmd(option, &cobra.Command{
+		Use:   "join CHANNEL --peer ORG:PEER",
+		Short: "Join peers of channel members to a channel",
+	}, "joined", JoinPeers)
+}
+
+func newLeaveCmd(option common.Options) *cobra.Command {
+	return peersCmd(option, &cobra.Command{
+		Use:   "leave CHANNEL --peer ORG:PEER",
+		Short: "Remove peers from a channel",
+	}, "left", LeavePeers)
+}
+
+func newAddMemberCmd(option common.Options) *cobra.Command {
+	var (
+		options proposal.Options
+		members []string
+	)
+	cmd := &cobra.Command{
+		Use:   "add-member CHANNEL --members ORG,ORG",
+		Short: "Propose to add organizations of the network to a channel",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			members = utils.RemoveDuplicateForStringSlice(members)
+			if len(members) == 0 {
+				return fmt.Errorf("no members provided")
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			channel, err := cli.Resource(channelGVR).Get(cmd.Context(), args[0], v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			net, netMembers, err := getNetwork(cmd.Context(), cli, utils.GetNestedString(channel.Object, "spec", "network"))
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			current := relation.Members(channel)
+			for _, member := range members {
+				if utils.ContainsString(current, member) {
+					return fmt.Errorf("organization %s is already a member of channel %s", member, channel.GetName())
+				}
+				if !utils.ContainsString(netMembers, member) {
+					return fmt.Errorf("organization %s is not a member of network %s", member, net.GetName())
+				}
+			}
+			if options.Initiator == "" {
+				if options.Initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+					return err
+				}
+			}
+			if !utils.ContainsString(current, options.Initiator) {
+				return fmt.Errorf("initiator %s is not a member of channel %s", options.Initiator, channel.GetName())
+			}
+			options.Policy = proposal.FederationPolicy(cmd.Context(), cli, utils.GetNestedString(net.Object, "spec", "federation"))
+
+			// the proposal carries all members of the channel after the update
+			specMembers := make([]interface{}, 0, len(current)+len(members))
+			for _, member := range append(current, members...) {
+				specMembers = append(specMembers, map[string]interface{}{"name": member, "initiator": member == options.Initiator})
+			}
+			p := proposal.NewProposal(options, proposal.SourceUpdateChannel, map[string]interface{}{
+				"channel": channel.GetName(),
+				"members": specMembers,
+			})
+			return proposal.CreateAndReport(cmd.Context(), cli, option.Out, p)
+		},
+	}
+	proposal.AddFlags(cmd, &options)
+	cmd.Flags().StringSliceVar(&members, "members", nil, "organizations to add")
+	return cmd
+}
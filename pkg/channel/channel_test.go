@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestParsePeers(t *testing.T) {
+	peers, err := ParsePeers([]string{"org1:peer0", "org2:peer1", "org1:peer0"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Peer{{Namespace: "org1", Name: "peer0"}, {Namespace: "org2", Name: "peer1"}}, peers)
+
+	_, err = ParsePeers([]string{"peer0"})
+	assert.Error(t, err)
+	_, err = ParsePeers([]string{"org1:"})
+	assert.Error(t, err)
+}
+
+func TestJoinAndLeavePeers(t *testing.T) {
+	ctx := context.Background()
+	peer0, peer1 := Peer{Namespace: "org1", Name: "peer0"}, Peer{Namespace: "org2", Name: "peer0"}
+	channel := NewChannel("ch1", "net1", "org1", []string{"org2"}, []Peer{peer0}, "")
+	cli := fake.NewSimpleDynamicClient(runtime.NewScheme(), channel)
+
+	_, err := JoinPeers(ctx, cli, channel, []Peer{{Namespace: "org3", Name: "peer0"}})
+	assert.ErrorContains(t, err, "does not belong")
+
+	joined, err := JoinPeers(ctx, cli, channel, []Peer{peer0, peer1})
+	assert.NoError(t, err)
+	assert.Equal(t, []Peer{peer0, peer1}, Peers(joined))
+
+	left, err := LeavePeers(ctx, cli, joined, []Peer{peer0})
+	assert.NoError(t, err)
+	assert.Equal(t, []Peer{peer1}, Peers(left))
+
+	_, err = LeavePeers(ctx, cli, left, []Peer{peer0})
+	assert.ErrorContains(t, err, "not in channel")
+}
+
+func TestDescribe(t *testing.T) {
+	channel := NewChannel("ch1", "net1", "org1", []string{"org2"}, []Peer{{Namespace: "org1", Name: "peer0"}, {Namespace: "org2", Name: "peer0"}}, "")
+	assert.NoError(t, unstructured.SetNestedSlice(channel.Object, []interface{}{
+		map[string]interface{}{"namespace": "org1", "name": "peer0", "type": "PeerJoined", "status": "True"},
+		map[string]interface{}{"namespace": "org3", "name": "peer0", "type": "PeerJoined", "status": "True"},
+		map[string]interface{}{"namespace": "org2", "name": "peer0", "type": "PeerError", "status": "True", "reason": "timeout"},
+	}, "status", "peerConditions"))
+
+	statuses := PeerStatuses(channel)
+	assert.Equal(t, []PeerStatus{
+		{Peer: Peer{Namespace: "org1", Name: "peer0"}, Status: PeerJoined},
+		{Peer: Peer{Namespace: "org2", Name: "peer0"}, Status: PeerError, Reason: "timeout"},
+		{Peer: Peer{Namespace: "org3", Name: "peer0"}, Status: PeerLeaving},
+	}, statuses)
+
+	out := new(bytes.Buffer)
+	Describe(out, channel)
+	assert.Contains(t, out.String(), "Members:  org1 (initiator), org2")
+	assert.Contains(t, out.String(), "org2:peer0  Error    timeout")
+}
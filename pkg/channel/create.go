@@ -0,0 +1,200 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+var (
+	channelGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Channel}
+	networkGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.NetworkResource}
+)
+
+// Peer is a peer node of an organization, the namespace of a peer is its organization
+type Peer struct {
+	Namespace string
+	Name      string
+}
+
+func (p Peer) String() string {
+	return p.Namespace + ":" + p.Name
+}
+
+// ParsePeers parses peers like org1:peer0
+func ParsePeers(peers []string) ([]Peer, error) {
+	result := make([]Peer, 0, len(peers))
+	for _, peer := range utils.RemoveDuplicateForStringSlice(peers) {
+		ns, name, ok := strings.Cut(peer, ":")
+		if !ok || ns == "" || name == "" {
+			return nil, fmt.Errorf("invalid peer %q, must be ORG:PEER", peer)
+		}
+		result = append(result, Peer{Namespace: ns, Name: name})
+	}
+	return result, nil
+}
+
+// Peers returns the peers in spec of channel
+func Peers(channel *unstructured.Unstructured) []Peer {
+	raw, _, _ := unstructured.NestedSlice(channel.Object, "spec", "peers")
+	peers := make([]Peer, 0, len(raw))
+	for _, item := range raw {
+		if m, ok := item.(map[string]interface{}); ok {
+			peers = append(peers, Peer{
+				Namespace: utils.GetNestedString(m, "namespace"),
+				Name:      utils.GetNestedString(m, "name"),
+			})
+		}
+	}
+	return peers
+}
+
+func peersToSpec(peers []Peer) []interface{} {
+	spec := make([]interface{}, 0, len(peers))
+	for _, p := range peers {
+		spec = append(spec, map[string]interface{}{"namespace": p.Namespace, "name": p.Name})
+	}
+	return spec
+}
+
+// NewChannel builds the Channel CR of network initiated by initiator with members and their peers
+func NewChannel(name string, net string, initiator string, members []string, peers []Peer, description string) *unstructured.Unstructured {
+	specMembers := []interface{}{
+		map[string]interface{}{"name": initiator, "initiator": true},
+	}
+	for _, member := range utils.RemoveDuplicateForStringSlice(members) {
+		if member != initiator {
+			specMembers = append(specMembers, map[string]interface{}{"name": member})
+		}
+	}
+	spec := map[string]interface{}{
+		"license": map[string]interface{}{
+			"accept": true,
+		},
+		"network": net,
+		"members": specMembers,
+		"peers":   peersToSpec(peers),
+	}
+	if description != "" {
+		spec["description"] = description
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "Channel",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": spec,
+	}}
+}
+
+// checkPeers returns an error if any peer does not belong to members
+func checkPeers(peers []Peer, members []string) error {
+	for _, p := range peers {
+		if !utils.ContainsString(members, p.Namespace) {
+			return fmt.Errorf("peer %s does not belong to any member of the channel", p)
+		}
+	}
+	return nil
+}
+
+// getNetwork returns the network and its members
+func getNetwork(ctx context.Context, cli dynamic.Interface, name string) (*unstructured.Unstructured, []string, error) {
+	net, err := cli.Resource(networkGVR).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return net, relation.Members(net), nil
+}
+
+func NewChanCreateCmd(option common.Options) *cobra.Command {
+	var (
+		net         string
+		initiator   string
+		members     []string
+		peers       []string
+		description string
+	)
+	cmd := &cobra.Command{
+		Use:   "channel NAME --network NETWORK --members ORG,ORG [--peers ORG:PEER,ORG:PEER]",
+		Short: "Create a channel in a network",
+		Long: `Create a channel in a network with some of the network's members.
+
+Unlike federations and networks, creating a channel creates no proposal. Every member of the channel
+has already approved the network it belongs to, and the operator has no proposal to create channels.
+Organizations added later are voted on by the existing members, see bc-cli channel add-member.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			parsedPeers, err := ParsePeers(peers)
+			if err != nil {
+				return err
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			_, netMembers, err := getNetwork(cmd.Context(), cli, net)
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if initiator == "" {
+				if initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+					return err
+				}
+			}
+			members = utils.RemoveDuplicateForStringSlice(append([]string{initiator}, members...))
+			for _, member := range members {
+				if !utils.ContainsString(netMembers, member) {
+					return fmt.Errorf("organization %s is not a member of network %s", member, net)
+				}
+			}
+			if err := checkPeers(parsedPeers, members); err != nil {
+				return err
+			}
+
+			channel := NewChannel(args[0], net, initiator, members, parsedPeers, description)
+			if _, err := cli.Resource(channelGVR).Create(cmd.Context(), channel, v1.CreateOptions{}); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fmt.Fprintf(option.Out, "channel/%s created\n", channel.GetName())
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&net, "network", "n", "", "network of the channel")
+	cmd.Flags().StringVar(&initiator, "initiator", "", "organization to create the channel, default to the only organization administered by the current user")
+	cmd.Flags().StringSliceVar(&members, "members", nil, "organizations of the channel")
+	cmd.Flags().StringSliceVar(&peers, "peers", nil, "peers to join the channel, such as org1:peer0")
+	cmd.Flags().StringVar(&description, "description", "", "description of the channel")
+	_ = cmd.MarkFlagRequired("network")
+	return cmd
+}
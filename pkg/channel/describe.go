@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channel
+
+import (
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// Join status of peers
+const (
+	PeerJoined  = "Joined"
+	PeerError   = "Error"
+	PeerPending = "Pending"
+	// PeerLeaving is the status of peers which are removed from spec but still reported in status
+	PeerLeaving = "Leaving"
+)
+
+// PeerStatus is the join status of a peer in a channel
+type PeerStatus struct {
+	Peer   Peer
+	Status string
+	Reason string
+}
+
+// PeerStatuses combines spec.peers with status.peerConditions of channel
+func PeerStatuses(channel *unstructured.Unstructured) []PeerStatus {
+	conditions := map[Peer]PeerStatus{}
+	order := make([]Peer, 0)
+	raw, _, _ := unstructured.NestedSlice(channel.Object, "status", "peerConditions")
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		p := Peer{Namespace: utils.GetNestedString(m, "namespace"), Name: utils.GetNestedString(m, "name")}
+		s := PeerStatus{Peer: p, Status: PeerPending, Reason: utils.GetNestedString(m, "reason")}
+		switch {
+		case utils.GetNestedString(m, "type") == "PeerJoined" && utils.GetNestedString(m, "status") == "True":
+			s.Status = PeerJoined
+		case utils.GetNestedString(m, "type") == "PeerError":
+			s.Status = PeerError
+		}
+		if _, found := conditions[p]; !found {
+			order = append(order, p)
+		}
+		conditions[p] = s
+	}
+
+	statuses := make([]PeerStatus, 0, len(order))
+	specPeers := Peers(channel)
+	for _, p := range specPeers {
+		s, found := conditions[p]
+		if !found {
+			s = PeerStatus{Peer: p, Status: PeerPending}
+		}
+		statuses = append(statuses, s)
+	}
+	for _, p := range order {
+		if !containsPeer(specPeers, p) {
+			s := conditions[p]
+			s.Status = PeerLeaving
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses
+}
+
+// Describe prints channel with the join status of its peers
+func Describe(out io.Writer, channel *unstructured.Unstructured) {
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	describeDetails(describe.NewPrefixWriter(tw), channel)
+	_ = tw.Flush()
+}
+
+func describeDetails(w describe.PrefixWriter, channel *unstructured.Unstructured) {
+	status := common.StatusType(channel)
+	if status == "" {
+		status = "<none>"
+	}
+	w.Write(describe.LEVEL_0, "Name:\t%s\n", channel.GetName())
+	w.Write(describe.LEVEL_0, "Network:\t%s\n", utils.GetNestedString(channel.Object, "spec", "network"))
+	w.Write(describe.LEVEL_0, "Status:\t%s\n", status)
+	w.Write(describe.LEVEL_0, "Members:\t%s\n", relation.FormatMembers(channel))
+	if description := utils.GetNestedString(channel.Object, "spec", "description"); description != "" {
+		w.Write(describe.LEVEL_0, "Description:\t%s\n", description)
+	}
+	w.Write(describe.LEVEL_0, "Created:\t%s\n", channel.GetCreationTimestamp().Format(time.RFC3339))
+	w.Write(describe.LEVEL_0, "Peers:\n")
+	statuses := PeerStatuses(channel)
+	if len(statuses) == 0 {
+		w.Write(describe.LEVEL_1, "<none>\n")
+	} else {
+		w.Write(describe.LEVEL_1, "PEER\tSTATUS\tREASON\n")
+		for _, s := range statuses {
+			reason := s.Reason
+			if reason == "" {
+				reason = "<none>"
+			}
+			w.Write(describe.LEVEL_1, "%s\t%s\t%s\n", s.Peer, s.Status, reason)
+		}
+	}
+}
+
+func NewChanDescribeCmd(option common.Options) *cobra.Command {
+	return relation.NewDescribeCmd(option, relation.KindChannel, &cobra.Command{
+		Use:     "channel NAME...",
+		Aliases: []string{"ch"},
+		Short:   "Show details of channels with the join status of their peers, related resources and events",
+	}, describeDetails)
+}
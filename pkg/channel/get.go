@@ -21,108 +21,88 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/spf13/viper"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/kubectl/pkg/cmd/get"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/network"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/resource"
+	"github.com/bestchains/bc-cli/pkg/utils"
 )
 
+// Kind is the kind of channels, which are visible to the members of the federations of their networks
+var Kind = resource.Kind{
+	Kind:    relation.KindChannel,
+	Name:    "channel",
+	Aliases: []string{"ch"},
+	GVR:     schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Channel},
+	Visible: func(ctx context.Context, cli dynamic.Interface) (func(*unstructured.Unstructured) bool, error) {
+		orgNames, err := org.ListUserOrganizations(cli, viper.GetString("auth.username"))
+		if err != nil {
+			return nil, err
+		}
+		return VisibleTo(ctx, cli, orgNames), nil
+	},
+	Columns: []resource.Column{
+		{Name: "Network", Value: resource.Field("spec", "network")},
+		{Name: "Members", Value: resource.Count("spec", "members")},
+		{Name: "Peers", Value: resource.Count("spec", "peers")},
+		{Name: "Status", Value: func(obj *unstructured.Unstructured) interface{} { return resource.OrNone(relation.Status(obj)) }},
+		{Name: "ID", Wide: true, Value: resource.Field("spec", "id")},
+	},
+}
+
 // NewChanGetCmd returns the `kubectl get` command for channel.
 func NewChanGetCmd(option common.Options) *cobra.Command {
-
-	defaultPrintFlag := get.NewGetPrintFlags()
+	flags := resource.NewGetFlags()
 
 	cmd := &cobra.Command{
-		Use: "channel [NAME] -n NETWORK-NAME",
+		Use:   "channel [NAME] -n NETWORK-NAME",
+		Short: "Get a list of channel in a network",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// get config from getter
+			cmd.SilenceUsage = true
 			cli, err := common.GetDynamicClient()
 			if err != nil {
 				fmt.Fprintln(option.ErrOut, err)
 				return err
 			}
-			list := corev1.List{
-				TypeMeta: v1.TypeMeta{
-					Kind:       "List",
-					APIVersion: "v1",
-				},
-				ListMeta: v1.ListMeta{},
-			}
-			var obj runtime.Object
-
-			netName, _ := cmd.Flags().GetString("network")
 
+			options := resource.Options{Names: args}
 			if len(args) == 0 {
 				// list all channel of the given network
-				net, err := cli.Resource(schema.GroupVersionResource{
-					Group:    common.IBPGroup,
-					Version:  common.IBPVersion,
-					Resource: common.NetworkResource,
-				}).Get(context.TODO(), netName, v1.GetOptions{})
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return err
-				}
-
-				channels, _, _ := unstructured.NestedStringSlice(net.Object, "status", "channels")
-
-				for _, chName := range channels {
-					ch, err := cli.Resource(schema.GroupVersionResource{
-						Group:    common.IBPGroup,
-						Version:  common.IBPVersion,
-						Resource: common.Channel,
-					}).Get(context.TODO(), chName, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						return err
-					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: ch})
-				}
-
-			} else {
-				// get channel by name
-				for _, arg := range args {
-					ch, err := cli.Resource(schema.GroupVersionResource{
-						Group:    common.IBPGroup,
-						Version:  common.IBPVersion,
-						Resource: common.Channel,
-					}).Get(context.TODO(), arg, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						continue
-					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: ch})
+				netName, _ := cmd.Flags().GetString("network")
+				options.Filter = func(ch *unstructured.Unstructured) bool {
+					return utils.GetNestedString(ch.Object, "spec", "network") == netName
 				}
 			}
-
-			// object process
-			if len(list.Items) != 1 {
-				obj, err = common.ListToObj(list)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return err
-				}
-			} else {
-				obj = list.Items[0].Object
-			}
-
-			// print result
-			p, err := defaultPrintFlag.ToPrinter()
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return err
-			}
-			_ = p.PrintObj(obj, option.Out)
-			return nil
+			return flags.Run(cmd.Context(), cli, option, Kind, options)
 		},
 	}
 
 	cmd.Flags().StringP("network", "n", "", "network of the desired channel")
 	_ = cmd.MarkFlagRequired("network")
+	flags.AddFlags(cmd)
 
 	return cmd
 }
+
+// VisibleTo returns a filter keeping channels of networks which are visible to any of organizations.
+// Whether a network is visible is looked up once and cached, so each network is fetched at most once.
+func VisibleTo(ctx context.Context, cli dynamic.Interface, organizations []string) func(*unstructured.Unstructured) bool {
+	visible := network.VisibleTo(ctx, cli, organizations)
+	networks := make(map[string]bool)
+	return func(ch *unstructured.Unstructured) bool {
+		netName := utils.GetNestedString(ch.Object, "spec", "network")
+		if ok, cached := networks[netName]; cached {
+			return ok
+		}
+		net, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Network}).Get(ctx, netName, v1.GetOptions{})
+		networks[netName] = err == nil && visible(net)
+		return networks[netName]
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package depository provides a typed client for the bc-saas depository apis.
+package depository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/bestchains/bc-cli/pkg/client"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/nonce"
+	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
+)
+
+var formHeaders = map[string]string{
+	"Content-Type": "application/x-www-form-urlencoded",
+}
+
+// Client talks to a depository server
+type Client struct {
+	server string
+	client *uhttp.Client
+}
+
+// NewClient returns a client of the depository server, such as http://localhost:9999
+func NewClient(server string) (*Client, error) {
+	c, err := uhttp.ClientFor(server)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{server: server, client: c}, nil
+}
+
+// CurrentNonce returns the current nonce of account
+func (c *Client) CurrentNonce(ctx context.Context, account string) (uint64, error) {
+	return nonce.Get(ctx, c.server, common.DepositoryCurrentNonce, account)
+}
+
+// PutUntrustValue stores value without the endorsement of any account
+func (c *Client) PutUntrustValue(ctx context.Context, value ValueDepository) (*PutValueResponse, error) {
+	encoded, err := value.Encode()
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Add("value", encoded)
+	return c.putValue(ctx, common.CreateUntrustedDepository, form)
+}
+
+// PutValue stores value with the endorsement of signer
+func (c *Client) PutValue(ctx context.Context, signer client.Signer, value ValueDepository) (*PutValueResponse, error) {
+	encoded, err := value.Encode()
+	if err != nil {
+		return nil, err
+	}
+	currNonce, err := c.CurrentNonce(ctx, signer.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+	msg, err := signer.GenerateAndSignMessage(currNonce, encoded)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Add("message", msg)
+	form.Add("value", encoded)
+	return c.putValue(ctx, common.CreateDepository, form)
+}
+
+func (c *Client) putValue(ctx context.Context, path string, form url.Values) (*PutValueResponse, error) {
+	data, err := c.client.Do(ctx, c.server+path, http.MethodPost, formHeaders, []byte(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	// the value is stored even if the response is not the expected json
+	resp := &PutValueResponse{}
+	_ = json.Unmarshal(data, resp)
+	resp.Raw = data
+	return resp, nil
+}
+
+// Get returns the depository of kid
+func (c *Client) Get(ctx context.Context, kid string) (*Depository, error) {
+	data, err := c.client.Do(ctx, c.server+fmt.Sprintf(common.GetDepository, url.PathEscape(kid)), http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	d := &Depository{}
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("unmarshal response error %w", err)
+	}
+	return d, nil
+}
+
+// List returns the depositories matching options
+func (c *Client) List(ctx context.Context, options ListOptions) (*DepositoryList, error) {
+	u := fmt.Sprintf("%s%s?%s", c.server, common.ListDepository, options.Query().Encode())
+	data, err := c.client.Do(ctx, u, http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	list := &DepositoryList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("unmarshal response error %w", err)
+	}
+	return list, nil
+}
+
+// Certificate returns the pdf certificate of kid, style is the language of the certificate(CN or ENG).
+// The caller should close the returned body, size is -1 if unknown.
+func (c *Client) Certificate(ctx context.Context, kid string, style string) (body io.ReadCloser, size int64, err error) {
+	query := url.Values{}
+	query.Add("style", style)
+	u := fmt.Sprintf("%s%s?%s", c.server, fmt.Sprintf(common.DepositoryCertificate, url.PathEscape(kid)), query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.client.Send(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Body, resp.ContentLength, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package depository
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	bccontext "github.com/bestchains/bestchains-contracts/library/context"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bestchains/bc-cli/pkg/account"
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+func newTestAccount(t *testing.T) *account.Account {
+	wallet, err := account.NewLocalWallet(t.TempDir())
+	assert.NoError(t, err)
+	acc, err := account.NewAccount()
+	assert.NoError(t, err)
+	assert.NoError(t, wallet.StoreAccount(acc))
+	acc, err = wallet.GetAccount(acc.Address)
+	assert.NoError(t, err)
+	return acc
+}
+
+func TestPutValue(t *testing.T) {
+	acc := newTestAccount(t)
+	value := ValueDepository{Name: "test", ContentID: "hash", TrustedTimestamp: "123"}
+	plain := ValueDepository{Name: "plain"}
+	plainValue, err := plain.Encode()
+	assert.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case common.DepositoryCurrentNonce:
+			assert.Equal(t, acc.Address, r.URL.Query().Get("account"))
+			_, _ = w.Write([]byte(`{"nonce":7}`))
+		case common.CreateDepository:
+			assert.NoError(t, r.ParseForm())
+			msg := &bccontext.Message{}
+			assert.NoError(t, msg.FromBase64EncodedStr(r.PostForm.Get("message")))
+			assert.Equal(t, uint64(7), msg.Nonce)
+			sender, err := msg.VerifyAgainstArgs(r.PostForm.Get("value"))
+			assert.NoError(t, err)
+			assert.Equal(t, acc.Address, sender.String())
+			_, _ = w.Write([]byte(`{"kid":"kid-1"}`))
+		case common.CreateUntrustedDepository:
+			assert.NoError(t, r.ParseForm())
+			assert.Empty(t, r.PostForm.Get("message"))
+			if r.PostForm.Get("value") == plainValue {
+				_, _ = w.Write([]byte("stored"))
+				return
+			}
+			_, _ = w.Write([]byte(`{"kid":"kid-2"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	assert.NoError(t, err)
+
+	resp, err := client.PutValue(context.Background(), acc, value)
+	assert.NoError(t, err)
+	assert.Equal(t, "kid-1", resp.KID)
+
+	resp, err = client.PutUntrustValue(context.Background(), value)
+	assert.NoError(t, err)
+	assert.Equal(t, "kid-2", resp.KID)
+
+	// responses in other shapes are kept as is
+	resp, err = client.PutUntrustValue(context.Background(), plain)
+	assert.NoError(t, err)
+	assert.Empty(t, resp.KID)
+	assert.Equal(t, "stored", string(resp.Raw))
+}
+
+func TestGetAndList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case common.ListDepository:
+			assert.Equal(t, "10", r.URL.Query().Get("from"))
+			assert.Equal(t, "doc", r.URL.Query().Get("name"))
+			assert.False(t, r.URL.Query().Has("kid"))
+			_ = json.NewEncoder(w).Encode(DepositoryList{Data: []Depository{{KID: "kid-1"}}, Count: 11})
+		case "/basic/depositories/kid-1":
+			_ = json.NewEncoder(w).Encode(Depository{KID: "kid-1", Name: "doc"})
+		case "/basic/depositories/certificate/kid-1":
+			assert.Equal(t, "ENG", r.URL.Query().Get("style"))
+			_, _ = w.Write([]byte("%PDF"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"msg":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	assert.NoError(t, err)
+
+	list, err := client.List(context.Background(), ListOptions{From: 10, Name: "doc"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), list.Count)
+	assert.Equal(t, "kid-1", list.Data[0].KID)
+
+	d, err := client.Get(context.Background(), "kid-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "doc", d.Name)
+
+	_, err = client.Get(context.Background(), "kid-2")
+	assert.Error(t, err)
+
+	body, size, err := client.Certificate(context.Background(), "kid-1", "ENG")
+	assert.NoError(t, err)
+	defer body.Close()
+	raw, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "%PDF", string(raw))
+	assert.Equal(t, int64(4), size)
+}
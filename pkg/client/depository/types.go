@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package depository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// Depository is a value stored in the depository contract
+type Depository struct {
+	Index       string `json:"index" pg:"index"`
+	KID         string `json:"kid" pg:"kid,pk"`
+	Platform    string `json:"platform" pg:"platform"`
+	Operator    string `json:"operator" pg:"operator"`
+	Owner       string `json:"owner" pg:"owner"`
+	BlockNumber uint64 `json:"blockNumber" pg:"blockNumber"`
+
+	// Content related
+	Name             string `json:"name" pg:"name"`
+	ContentName      string `json:"contentName" pg:"contentName"`
+	ContentID        string `json:"contentID" pg:"contentID"`
+	ContentType      string `json:"contentType" pg:"contentType"`
+	TrustedTimestamp int64  `json:"trustedTimestamp" pg:"trustedTimestamp"`
+}
+
+// DepositoryList is the response of List
+type DepositoryList struct {
+	Data  []Depository `json:"data"`
+	Count int64        `json:"count"`
+}
+
+// ValueDepository is the value to put into the depository
+type ValueDepository struct {
+	Name             string `json:"name"`
+	ContentType      string `json:"contentType"`
+	ContentID        string `json:"contentID"` // hash of the file
+	TrustedTimestamp string `json:"trustedTimestamp"`
+	Platform         string `json:"platform"`
+}
+
+// Encode returns the base64 encoded json of the value, which is the format the server accepts.
+func (v ValueDepository) Encode() (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+// PutValueResponse is the response of PutValue and PutUntrustValue
+type PutValueResponse struct {
+	// KID is the key id of the stored value, it is empty if the server responds in another shape
+	KID string `json:"kid"`
+	// Raw is the response body as is
+	Raw []byte `json:"-"`
+}
+
+// ListOptions filters and paginates the depositories in List
+type ListOptions struct {
+	From        int
+	Size        int
+	KID         string
+	Name        string
+	ContentName string
+}
+
+// Query returns the url query of the options, empty options are omitted.
+func (o ListOptions) Query() url.Values {
+	query := url.Values{}
+	if o.From != 0 {
+		query.Add("from", strconv.Itoa(o.From))
+	}
+	if o.Size != 0 {
+		query.Add("size", strconv.Itoa(o.Size))
+	}
+	if o.KID != "" {
+		query.Add("kid", o.KID)
+	}
+	if o.Name != "" {
+		query.Add("name", o.Name)
+	}
+	if o.ContentName != "" {
+		query.Add("contentName", o.ContentName)
+	}
+	return query
+}
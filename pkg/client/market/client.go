@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package market provides a typed client for the bc-saas market apis.
+package market
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/bestchains/bc-cli/pkg/client"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/nonce"
+	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
+)
+
+// Client talks to a market server
+type Client struct {
+	server string
+	client *uhttp.Client
+}
+
+// NewClient returns a client of the market server, such as http://localhost:9998
+func NewClient(server string) (*Client, error) {
+	c, err := uhttp.ClientFor(server)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{server: server, client: c}, nil
+}
+
+// CurrentNonce returns the current nonce of account
+func (c *Client) CurrentNonce(ctx context.Context, account string) (uint64, error) {
+	return nonce.Get(ctx, c.server, common.MarketCurrentNonce, account)
+}
+
+// CreateRepo creates a repository of repoURL owned by signer
+func (c *Client) CreateRepo(ctx context.Context, signer client.Signer, repoURL string) (*CreateRepoResponse, error) {
+	currNonce, err := c.CurrentNonce(ctx, signer.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+	msg, err := signer.GenerateAndSignMessage(currNonce, repoURL)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Add("message", msg)
+	form.Add("url", repoURL)
+	data, err := c.client.Do(ctx, c.server+common.CreateRepository, http.MethodPost, map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	}, []byte(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	// the repository is created even if the response is not the expected json
+	resp := &CreateRepoResponse{}
+	_ = json.Unmarshal(data, resp)
+	resp.Raw = data
+	return resp, nil
+}
+
+// ListRepos returns the repositories matching options
+func (c *Client) ListRepos(ctx context.Context, options ListOptions) (*RepositoryList, error) {
+	u := fmt.Sprintf("%s%s?%s", c.server, common.ListRepositories, options.Query().Encode())
+	data, err := c.client.Do(ctx, u, http.MethodGet, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	list := &RepositoryList{}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("unmarshal response error %w", err)
+	}
+	return list, nil
+}
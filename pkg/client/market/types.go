@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package market
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Repository is a component repository in the market contract
+type Repository struct {
+	ID    string `json:"id,omitempty"`
+	Owner string `json:"owner,omitempty"`
+	URL   string `json:"url,omitempty"`
+}
+
+// RepositoryList is the response of ListRepos
+type RepositoryList struct {
+	Data  []Repository `json:"data"`
+	Count int64        `json:"count"`
+}
+
+// CreateRepoResponse is the response of CreateRepo
+type CreateRepoResponse struct {
+	// ID is the id of the created repository, it is empty if the server responds in another shape
+	ID string `json:"id"`
+	// Raw is the response body as is
+	Raw []byte `json:"-"`
+}
+
+// ListOptions filters and paginates the repositories in ListRepos
+type ListOptions struct {
+	From  int
+	Size  int
+	Owner string
+}
+
+// Query returns the url query of the options, empty options are omitted.
+func (o ListOptions) Query() url.Values {
+	query := url.Values{}
+	if o.From != 0 {
+		query.Add("from", strconv.Itoa(o.From))
+	}
+	if o.Size != 0 {
+		query.Add("size", strconv.Itoa(o.Size))
+	}
+	if o.Owner != "" {
+		query.Add("owner", o.Owner)
+	}
+	return query
+}
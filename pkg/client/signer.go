@@ -0,0 +1,27 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package client provides typed clients for the bc-saas http apis.
+package client
+
+// Signer signs the message of requests which need the endorsement of an account.
+// *account.Account implements it.
+type Signer interface {
+	// GetAddress returns the address of the account
+	GetAddress() string
+	// GenerateAndSignMessage returns the base64 encoded message signed with nonce and args
+	GenerateAndSignMessage(nonce uint64, args ...string) (string, error)
+}
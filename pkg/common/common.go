@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -62,3 +63,26 @@ var (
 type Options struct {
 	genericclioptions.IOStreams
 }
+
+// AnnotationRequireLogin marks a command which talks to remote services,
+// so a valid token is required before it runs.
+const AnnotationRequireLogin = "bestchains.io/require-login"
+
+// RequireLogin marks cmd and all of its subcommands as requiring a valid token.
+func RequireLogin(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[AnnotationRequireLogin] = "true"
+	return cmd
+}
+
+// LoginRequired reports whether cmd or any of its parents is marked by RequireLogin.
+func LoginRequired(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[AnnotationRequireLogin] == "true" {
+			return true
+		}
+	}
+	return false
+}
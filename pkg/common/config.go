@@ -39,37 +39,41 @@ type ClusterConfig struct {
 	LocationOfOrigin string
 	// Server is the address of the kubernetes cluster (https://hostname:port).
 	Server string `mapstructure:"server"`
-	// TLSServerName is used to check server certificate. If TLSServerName is empty, the hostname used to contact the server is used.
-	// +optional
-	TLSServerName string `mapstructure:"tls-server-name,omitempty"`
-	// InsecureSkipTLSVerify skips the validity check for the server's certificate. This will make your HTTPS connections insecure.
-	// +optional
-	InsecureSkipTLSVerify bool `mapstructure:"insecure-skip-tls-verify,omitempty"`
-	// CertificateAuthority is the path to a cert file for the certificate authority.
-	// +optional
-	CertificateAuthority string `mapstructure:"certificate-authority,omitempty"`
-	// CertificateAuthorityData contains PEM-encoded certificate authority certificates. Overrides CertificateAuthority
-	// +optional
-	CertificateAuthorityData []byte `mapstructure:"certificate-authority-data,omitempty"`
-	// ProxyURL is the URL to the proxy to be used for all requests made by this
-	// client. URLs with "http", "https", and "socks5" schemes are supported.  If
-	// this configuration is not provided or the empty string, the client
-	// attempts to construct a proxy configuration from http_proxy and
-	// https_proxy environment variables. If these environment variables are not
-	// set, the client does not attempt to proxy requests.
-	//
-	// socks5 proxying does not currently support spdy streaming endpoints (exec,
-	// attach, port forward).
-	// +optional
-	ProxyURL string `mapstructure:"proxy-url,omitempty"`
+	// TLSConfig is used to communicate with the cluster, as the saas endpoints do.
+	// Before TLSConfig was embedded, the *Data fields were []byte which are saved as base64, they are still accepted.
+	TLSConfig `mapstructure:",squash"`
 	// DisableCompression allows client to opt-out of response compression for all requests to the server. This is useful
 	// to speed up requests (specifically lists) when client-server network bandwidth is ample, by saving time on
 	// compression (server-side) and decompression (client-side): https://github.com/kubernetes/kubernetes/issues/112296.
 	// +optional
-	DisableCompression bool `mapstructure:"disable-compression,omitempty"`
+	DisableCompression bool `mapstructure:"disable-compression,omitempty" json:"disable-compression,omitempty"`
 	// Extensions holds additional information. This is useful for extenders so that reads and writes don't clobber unknown fields
 	// +optional
-	Extensions map[string]runtime.Object `mapstructure:"extensions,omitempty"`
+	Extensions map[string]runtime.Object `mapstructure:"extensions,omitempty" json:"extensions,omitempty"`
+}
+
+// TLSConfig contains the settings to communicate with a https server.
+// The *Data fields accept either PEM or base64 encoded PEM, and override the corresponding file paths.
+type TLSConfig struct {
+	// TLSServerName is used to check server certificate. If TLSServerName is empty, the hostname used to contact the server is used.
+	TLSServerName string `mapstructure:"tls-server-name,omitempty" json:"tls-server-name,omitempty"`
+	// InsecureSkipTLSVerify skips the validity check for the server's certificate. This will make your HTTPS connections insecure.
+	InsecureSkipTLSVerify bool `mapstructure:"insecure-skip-tls-verify,omitempty" json:"insecure-skip-tls-verify,omitempty"`
+	// CertificateAuthority is the path to a cert file for the certificate authority.
+	CertificateAuthority string `mapstructure:"certificate-authority,omitempty" json:"certificate-authority,omitempty"`
+	// CertificateAuthorityData contains PEM-encoded certificate authority certificates.
+	CertificateAuthorityData string `mapstructure:"certificate-authority-data,omitempty" json:"certificate-authority-data,omitempty"`
+	// ClientCertificate is the path to a client cert file for mutual TLS.
+	ClientCertificate string `mapstructure:"client-certificate,omitempty" json:"client-certificate,omitempty"`
+	// ClientCertificateData contains PEM-encoded data of a client cert.
+	ClientCertificateData string `mapstructure:"client-certificate-data,omitempty" json:"client-certificate-data,omitempty"`
+	// ClientKey is the path to a client key file for mutual TLS.
+	ClientKey string `mapstructure:"client-key,omitempty" json:"client-key,omitempty"`
+	// ClientKeyData contains PEM-encoded data of a client key.
+	ClientKeyData string `mapstructure:"client-key-data,omitempty" json:"client-key-data,omitempty"`
+	// ProxyURL is the URL to the proxy to be used for all requests to this server.
+	// If empty, http_proxy and https_proxy environment variables are used.
+	ProxyURL string `mapstructure:"proxy-url,omitempty" json:"proxy-url,omitempty"`
 }
 
 type AuthConfig struct {
@@ -88,6 +92,9 @@ type AuthConfig struct {
 
 	ClientID     string `mapstructure:"clientid"`
 	ClientSecret string `mapstructure:"clientsecret"`
+
+	// TLSConfig is used to communicate with the OIDC issuer
+	TLSConfig `mapstructure:",squash"`
 }
 
 // SaasConfig represents the configuration for a SaaS application.
@@ -96,18 +103,40 @@ type SaasConfig struct {
 	Depository Depository `mapstructure:"depository"`
 	// Market represents the configuration for the market server.
 	Market Market `mapstructure:"market"`
+	// ObjectStore represents the configuration for the object store which keeps chaincode sources.
+	ObjectStore ObjectStore `mapstructure:"objectstore"`
 }
 
 // Depository represents the configuration for the depository server.
 type Depository struct {
 	// Server represents the URL of the depository server.
 	Server string `mapstructure:"server"`
+	// TLSConfig is used to communicate with the depository server.
+	TLSConfig `mapstructure:",squash"`
 }
 
 // Market represents the configuration for the market server.
 type Market struct {
 	// Server represents the URL of the market server.
 	Server string `mapstructure:"server"`
+	// TLSConfig is used to communicate with the market server.
+	TLSConfig `mapstructure:",squash"`
+}
+
+// ObjectStore represents the configuration for the s3 compatible object store, such as minio.
+type ObjectStore struct {
+	// Server represents the URL of the object store. A file:// URL uses a local directory instead,
+	// which is handy for testing without the platform.
+	Server string `mapstructure:"server"`
+	// Bucket is the bucket to upload chaincode sources to.
+	Bucket string `mapstructure:"bucket"`
+	// Region is the region of the bucket, us-east-1 if empty.
+	Region string `mapstructure:"region,omitempty" json:"region,omitempty"`
+	// AccessKey and SecretKey are the credentials to sign requests, requests are anonymous if empty.
+	AccessKey string `mapstructure:"access-key,omitempty" json:"access-key,omitempty"`
+	SecretKey string `mapstructure:"secret-key,omitempty" json:"secret-key,omitempty"`
+	// TLSConfig is used to communicate with the object store.
+	TLSConfig `mapstructure:",squash"`
 }
 
 const (
@@ -46,6 +46,7 @@ const (
 	EndorsePolicy        = "endorsepolicies"
 	ChaincodeBuild       = "chaincodebuilds"
 	Chaincode            = "chaincodes"
+	IBPPeer              = "ibppeers"
 )
 
 func InKubeGetter() (*clientcmdapi.Config, error) {
@@ -55,14 +56,36 @@ func InKubeGetter() (*clientcmdapi.Config, error) {
 	idToken := viper.GetString("auth.idtoken")
 	issuerUrl := viper.GetString("auth.issuerurl")
 	refreshToken := viper.GetString("auth.refreshtoken")
+	tlsConfig := GetTLSConfig("cluster")
+	caData, err := loadPEM(tlsConfig.CertificateAuthority, tlsConfig.CertificateAuthorityData)
+	if err != nil {
+		return nil, err
+	}
+	certData, err := loadPEM(tlsConfig.ClientCertificate, tlsConfig.ClientCertificateData)
+	if err != nil {
+		return nil, err
+	}
+	keyData, err := loadPEM(tlsConfig.ClientKey, tlsConfig.ClientKeyData)
+	if err != nil {
+		return nil, err
+	}
+	insecure := tlsConfig.InsecureSkipTLSVerify || InsecureSkipTLSVerify
+	if insecure {
+		WarnInsecure(kubeOIDCProxy)
+		// client-go refuses to use a root certificate together with insecure
+		caData = nil
+	}
 
 	return &clientcmdapi.Config{
 		Kind:       "Config",
 		APIVersion: "v1",
 		Clusters: map[string]*clientcmdapi.Cluster{
 			"kube-oidc-proxy": {
-				Server:                kubeOIDCProxy,
-				InsecureSkipTLSVerify: true,
+				Server:                   kubeOIDCProxy,
+				TLSServerName:            tlsConfig.TLSServerName,
+				InsecureSkipTLSVerify:    insecure,
+				CertificateAuthorityData: caData,
+				ProxyURL:                 tlsConfig.ProxyURL,
 			},
 		},
 		Contexts: map[string]*clientcmdapi.Context{
@@ -74,6 +97,8 @@ func InKubeGetter() (*clientcmdapi.Config, error) {
 		CurrentContext: "oidc@kube-oidc-proxy",
 		AuthInfos: map[string]*clientcmdapi.AuthInfo{
 			"oidc": {
+				ClientCertificateData: certData,
+				ClientKeyData:         keyData,
 				AuthProvider: &clientcmdapi.AuthProviderConfig{
 					Name: "oidc",
 					Config: map[string]string{
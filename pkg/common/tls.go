@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// InsecureSkipTLSVerify is set by the global --insecure-skip-tls-verify flag,
+// it disables the certificate check for all servers.
+var InsecureSkipTLSVerify bool
+
+var (
+	warnedMu sync.Mutex
+	warned   = map[string]struct{}{}
+)
+
+// WarnInsecure prints a warning once for each server whose certificate is not verified.
+func WarnInsecure(server string) {
+	warnedMu.Lock()
+	defer warnedMu.Unlock()
+	if _, ok := warned[server]; ok {
+		return
+	}
+	warned[server] = struct{}{}
+	fmt.Fprintf(os.Stderr, "WARNING: the certificate of %s will not be verified, the connection is insecure\n", server)
+}
+
+// NewTLSConfig builds a *tls.Config with the CA, client certificate and server name in cfg.
+func NewTLSConfig(cfg TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.InsecureSkipTLSVerify || InsecureSkipTLSVerify,
+	}
+
+	caData, err := loadPEM(cfg.CertificateAuthority, cfg.CertificateAuthorityData)
+	if err != nil {
+		return nil, fmt.Errorf("load certificate authority: %w", err)
+	}
+	if len(caData) != 0 && !tlsConfig.InsecureSkipVerify {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no valid certificate found in certificate authority")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certData, err := loadPEM(cfg.ClientCertificate, cfg.ClientCertificateData)
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate: %w", err)
+	}
+	keyData, err := loadPEM(cfg.ClientKey, cfg.ClientKeyData)
+	if err != nil {
+		return nil, fmt.Errorf("load client key: %w", err)
+	}
+	if len(certData) != 0 || len(keyData) != 0 {
+		cert, err := tls.X509KeyPair(certData, keyData)
+		if err != nil {
+			return nil, fmt.Errorf("load client key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// NewTransport returns a *http.Transport for server which honors the tls and proxy settings in cfg.
+func NewTransport(server string, cfg TLSConfig) (*http.Transport, error) {
+	tlsConfig, err := NewTLSConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("tls config for %s: %w", server, err)
+	}
+	if tlsConfig.InsecureSkipVerify {
+		WarnInsecure(server)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url %s: %w", cfg.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport, nil
+}
+
+// endpoints maps the config key of each server to the key of its url
+var endpoints = []struct{ key, server string }{
+	{key: "saas.depository", server: "saas.depository.server"},
+	{key: "saas.market", server: "saas.market.server"},
+	{key: "saas.objectstore", server: "saas.objectstore.server"},
+	{key: "auth", server: "auth.issuerurl"},
+	{key: "cluster", server: "cluster.server"},
+}
+
+// GetTLSConfig returns the tls settings under key of the config file, such as `saas.depository`
+func GetTLSConfig(key string) TLSConfig {
+	cfg := TLSConfig{}
+	_ = viper.UnmarshalKey(key, &cfg)
+	return cfg
+}
+
+// TLSConfigForURL returns the tls settings of the configured server which rawURL belongs to.
+// An empty TLSConfig is returned if rawURL matches none of them.
+func TLSConfigForURL(rawURL string) TLSConfig {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return TLSConfig{}
+	}
+	for _, endpoint := range endpoints {
+		server, err := url.Parse(viper.GetString(endpoint.server))
+		if err != nil || server.Host != u.Host {
+			continue
+		}
+		return GetTLSConfig(endpoint.key)
+	}
+	return TLSConfig{}
+}
+
+// loadPEM returns data if not empty, otherwise the content of file.
+// data can be PEM or base64 encoded PEM.
+func loadPEM(file string, data string) ([]byte, error) {
+	if data != "" {
+		if bytes.Contains([]byte(data), []byte("-----BEGIN")) {
+			return []byte(data), nil
+		}
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if file != "" {
+		return os.ReadFile(file)
+	}
+	return nil, nil
+}
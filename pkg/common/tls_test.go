@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+// newClientCert generates a self-signed client certificate and returns its PEM encoded cert and key.
+func newClientCert(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "bc-cli"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func get(server string, cfg TLSConfig) error {
+	transport, err := NewTransport(server, cfg)
+	if err != nil {
+		return err
+	}
+	resp, err := (&http.Client{Transport: transport}).Get(server)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func TestNewTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	// unknown authority is rejected by default
+	assert.Error(t, get(server.URL, TLSConfig{}))
+
+	// certificate authority from file
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	assert.NoError(t, os.WriteFile(caFile, caPEM, 0644))
+	assert.NoError(t, get(server.URL, TLSConfig{CertificateAuthority: caFile}))
+
+	// certificate authority data, raw or base64 encoded
+	assert.NoError(t, get(server.URL, TLSConfig{CertificateAuthorityData: string(caPEM)}))
+	assert.NoError(t, get(server.URL, TLSConfig{CertificateAuthorityData: base64.StdEncoding.EncodeToString(caPEM)}))
+
+	// server name override must match the certificate
+	assert.Error(t, get(server.URL, TLSConfig{CertificateAuthorityData: string(caPEM), TLSServerName: "bestchains.io"}))
+	assert.NoError(t, get(server.URL, TLSConfig{CertificateAuthorityData: string(caPEM), TLSServerName: "example.com"}))
+
+	// explicit opt-in to skip verification
+	assert.NoError(t, get(server.URL, TLSConfig{InsecureSkipTLSVerify: true}))
+	InsecureSkipTLSVerify = true
+	assert.NoError(t, get(server.URL, TLSConfig{}))
+	InsecureSkipTLSVerify = false
+
+	// invalid settings
+	_, err := NewTransport(server.URL, TLSConfig{CertificateAuthority: filepath.Join(t.TempDir(), "not-exist")})
+	assert.Error(t, err)
+	_, err = NewTransport(server.URL, TLSConfig{ProxyURL: "://proxy"})
+	assert.Error(t, err)
+}
+
+func TestNewTransportWithClientCert(t *testing.T) {
+	certPEM, keyPEM := newClientCert(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(certPEM)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	assert.Error(t, get(server.URL, TLSConfig{CertificateAuthorityData: string(caPEM)}))
+	assert.NoError(t, get(server.URL, TLSConfig{
+		CertificateAuthorityData: string(caPEM),
+		ClientCertificateData:    string(certPEM),
+		ClientKeyData:            string(keyPEM),
+	}))
+}
+
+func TestClusterTLSConfigRoundTrip(t *testing.T) {
+	caPEM, _ := newClientCert(t)
+	config := Config{Cluster: ClusterConfig{Server: "https://example.com", TLSConfig: TLSConfig{CertificateAuthorityData: string(caPEM)}}}
+	// the config file is saved as json then read back by viper, like PersistentPostRunE does
+	data, err := json.Marshal(config)
+	assert.NoError(t, err)
+	v := viper.New()
+	v.SetConfigType("json")
+	assert.NoError(t, v.ReadConfig(bytes.NewReader(data)))
+	cfg := TLSConfig{}
+	assert.NoError(t, v.UnmarshalKey("cluster", &cfg))
+	assert.Equal(t, string(caPEM), cfg.CertificateAuthorityData)
+}
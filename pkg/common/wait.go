@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// StatusDeployed is the status type of IBP resources which are ready
+	StatusDeployed = "Deployed"
+	// StatusCreated is the status type of IBP resources which are created but not deployed yet
+	StatusCreated = "Created"
+	// StatusError is the status type of IBP resources which fail to deploy
+	StatusError = "Error"
+)
+
+// WaitInterval is the interval to poll resources
+var WaitInterval = 2 * time.Second
+
+// StatusType returns status.type of an IBP resource
+func StatusType(obj *unstructured.Unstructured) string {
+	t, _, _ := unstructured.NestedString(obj.Object, "status", "type")
+	return t
+}
+
+// WaitForStatus polls the resource name until its status.type is one of types, and returns the last object.
+// It fails fast if the resource reports status type Error. timeout 0 means waiting until ctx is done.
+func WaitForStatus(ctx context.Context, ri dynamic.ResourceInterface, name string, timeout time.Duration, types ...string) (*unstructured.Unstructured, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var last *unstructured.Unstructured
+	err := wait.PollUntilContextCancel(ctx, WaitInterval, true, func(ctx context.Context) (bool, error) {
+		obj, err := ri.Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		last = obj
+		statusType := StatusType(obj)
+		for _, t := range types {
+			if statusType == t {
+				return true, nil
+			}
+		}
+		if statusType == StatusError {
+			reason, _, _ := unstructured.NestedString(obj.Object, "status", "reason")
+			message, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+			return false, fmt.Errorf("%s %s failed: %s %s", obj.GetKind(), name, reason, message)
+		}
+		return false, nil
+	})
+	if err != nil && wait.Interrupted(err) {
+		current := "<none>"
+		if last != nil && StatusType(last) != "" {
+			current = StatusType(last)
+		}
+		return last, fmt.Errorf("timed out waiting for %s to be %v, current status is %s", name, types, current)
+	}
+	return last, err
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestWaitForStatus(t *testing.T) {
+	WaitInterval = 10 * time.Millisecond
+	gvr := schema.GroupVersionResource{Group: IBPGroup, Version: IBPVersion, Resource: OrganizationResource}
+	newOrg := func(name, statusType string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(IBPGroup + "/" + IBPVersion)
+		obj.SetKind("Organization")
+		obj.SetName(name)
+		_ = unstructured.SetNestedField(obj.Object, statusType, "status", "type")
+		return obj
+	}
+	cli := fake.NewSimpleDynamicClient(runtime.NewScheme(), newOrg("pending", StatusCreated), newOrg("failed", StatusError))
+	ri := cli.Resource(gvr)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_, _ = ri.Update(context.Background(), newOrg("pending", StatusDeployed), v1.UpdateOptions{})
+	}()
+	obj, err := WaitForStatus(context.Background(), ri, "pending", time.Second, StatusDeployed)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusDeployed, StatusType(obj))
+
+	_, err = WaitForStatus(context.Background(), ri, "failed", time.Second, StatusDeployed)
+	assert.ErrorContains(t, err, "failed")
+
+	_, err = WaitForStatus(context.Background(), ri, "pending", 50*time.Millisecond, StatusCreated+"x")
+	assert.ErrorContains(t, err, "timed out")
+}
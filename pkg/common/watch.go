@@ -0,0 +1,187 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// WatchFlags are the flags of getters to stream changes after listing resources
+type WatchFlags struct {
+	Watch     bool
+	WatchOnly bool
+}
+
+// AddFlags adds --watch and --watch-only to cmd
+func (f *WatchFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&f.Watch, "watch", "w", false, "after listing the requested resources, watch for changes")
+	cmd.Flags().BoolVar(&f.WatchOnly, "watch-only", false, "watch for changes to the requested resources, without listing them first")
+}
+
+// Enabled reports whether changes should be watched
+func (f WatchFlags) Enabled() bool {
+	return f.Watch || f.WatchOnly
+}
+
+// WatchHandler handles an added, modified or deleted resource
+type WatchHandler func(eventType watch.EventType, obj *unstructured.Unstructured) error
+
+// Watch streams the changes of resources in ri selected by options until ctx is done.
+// Resources for which filter returns false are skipped, filter is used for the filtering done on the client side,
+// such as resources of the organizations of the current user. A nil filter keeps all resources.
+// The watch starts from the current resource version and is resumed from the last seen one if the server closes it.
+func Watch(ctx context.Context, ri dynamic.ResourceInterface, options v1.ListOptions, filter func(*unstructured.Unstructured) bool, handle WatchHandler) error {
+	if options.ResourceVersion == "" {
+		// only the resource version of the list is needed
+		list, err := ri.List(ctx, v1.ListOptions{LabelSelector: options.LabelSelector, FieldSelector: options.FieldSelector, Limit: 1})
+		if err != nil {
+			return err
+		}
+		options.ResourceVersion = list.GetResourceVersion()
+	}
+	for {
+		w, err := ri.Watch(ctx, options)
+		if err != nil {
+			return err
+		}
+		closed, err := consume(ctx, w, &options, filter, handle)
+		w.Stop()
+		if err != nil || !closed {
+			return err
+		}
+		klog.V(2).Infof("watch closed by the server, resume from resource version %s", options.ResourceVersion)
+	}
+}
+
+// consume handles the events of w, closed is true if the server closes w before ctx is done
+func consume(ctx context.Context, w watch.Interface, options *v1.ListOptions, filter func(*unstructured.Unstructured) bool, handle WatchHandler) (closed bool, err error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return true, nil
+			}
+			switch event.Type {
+			case watch.Error:
+				return false, apierrors.FromObject(event.Object)
+			case watch.Bookmark:
+				if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+					options.ResourceVersion = obj.GetResourceVersion()
+				}
+				continue
+			}
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				return false, fmt.Errorf("unexpected object %T in watch event", event.Object)
+			}
+			if rv := obj.GetResourceVersion(); rv != "" {
+				options.ResourceVersion = rv
+			}
+			if filter != nil && !filter(obj) {
+				continue
+			}
+			if err := handle(event.Type, obj); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+// WatchPrinter prints resources with the same printer as the list printed before watching,
+// so that table headers are only printed once
+type WatchPrinter struct {
+	printer printers.ResourcePrinter
+	out     io.Writer
+}
+
+// NewWatchPrinter returns a WatchPrinter printing to out with printer
+func NewWatchPrinter(printer printers.ResourcePrinter, out io.Writer) *WatchPrinter {
+	return &WatchPrinter{printer: printer, out: out}
+}
+
+// PrintObj prints obj and flushes it immediately
+func (p *WatchPrinter) PrintObj(obj runtime.Object) error {
+	w := printers.GetNewTabWriter(p.out)
+	if err := p.printer.PrintObj(obj, w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// Handler returns a WatchHandler printing each resource
+func (p *WatchPrinter) Handler() WatchHandler {
+	return func(_ watch.EventType, obj *unstructured.Unstructured) error {
+		return p.PrintObj(obj)
+	}
+}
+
+// HasName returns a filter keeping resources whose name is in names
+func HasName(names []string) func(*unstructured.Unstructured) bool {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return func(obj *unstructured.Unstructured) bool {
+		_, ok := set[obj.GetName()]
+		return ok
+	}
+}
+
+// Seen returns a filter keeping resources named in names and resources kept by filter,
+// whose names are remembered so that later changes of them are kept even if filter does not keep them anymore
+func Seen(names []string, filter func(*unstructured.Unstructured) bool) func(*unstructured.Unstructured) bool {
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		seen[name] = struct{}{}
+	}
+	return func(obj *unstructured.Unstructured) bool {
+		if _, ok := seen[obj.GetName()]; ok {
+			return true
+		}
+		if !filter(obj) {
+			return false
+		}
+		seen[obj.GetName()] = struct{}{}
+		return true
+	}
+}
+
+// ListNames returns the names of resources in list
+func ListNames(list corev1.List) []string {
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		if obj, ok := item.Object.(v1.Object); ok {
+			names = append(names, obj.GetName())
+		}
+	}
+	return names
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+	cmdget "k8s.io/kubectl/pkg/cmd/get"
+)
+
+func newWatchedOrg(name, resourceVersion string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(IBPGroup + "/" + IBPVersion)
+	obj.SetKind("Organization")
+	obj.SetName(name)
+	obj.SetResourceVersion(resourceVersion)
+	return obj
+}
+
+func TestWatch(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: IBPGroup, Version: IBPVersion, Resource: OrganizationResource}
+	cli := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	watchers := []*watch.FakeWatcher{watch.NewFake(), watch.NewFake()}
+	var resourceVersions []string
+	cli.PrependWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		resourceVersions = append(resourceVersions, action.(k8stesting.WatchActionImpl).WatchRestrictions.ResourceVersion)
+		w := watchers[0]
+		watchers = watchers[1:]
+		return true, w, nil
+	})
+	first, second := watchers[0], watchers[1]
+	go func() {
+		first.Add(newWatchedOrg("a", "1"))
+		first.Add(newWatchedOrg("b", "2"))
+		first.Stop()
+		second.Modify(newWatchedOrg("a", "3"))
+		second.Delete(newWatchedOrg("a", "4"))
+		second.Error(&apierrors.NewGone("too old").ErrStatus)
+	}()
+
+	var events []string
+	err := Watch(context.Background(), cli.Resource(gvr), v1.ListOptions{ResourceVersion: "0"}, HasName([]string{"a"}), func(eventType watch.EventType, obj *unstructured.Unstructured) error {
+		events = append(events, string(eventType)+" "+obj.GetName()+"@"+obj.GetResourceVersion())
+		return nil
+	})
+	assert.True(t, apierrors.IsGone(err))
+	assert.Equal(t, []string{"ADDED a@1", "MODIFIED a@3", "DELETED a@4"}, events)
+	// the watch is resumed from the last seen resource version, even of a filtered resource
+	assert.Equal(t, []string{"0", "2"}, resourceVersions)
+}
+
+func TestWatchCanceled(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: IBPGroup, Version: IBPVersion, Resource: OrganizationResource}
+	cli := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "OrganizationList"})
+	w := watch.NewFake()
+	cli.PrependWatchReactor("*", k8stesting.DefaultWatchReactor(w, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go w.Add(newWatchedOrg("a", "1"))
+	err := Watch(ctx, cli.Resource(gvr), v1.ListOptions{}, nil, func(watch.EventType, *unstructured.Unstructured) error {
+		cancel()
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestSeen(t *testing.T) {
+	visible := map[string]bool{"b": true}
+	filter := Seen([]string{"a"}, func(obj *unstructured.Unstructured) bool {
+		return visible[obj.GetName()]
+	})
+	assert.True(t, filter(newWatchedOrg("a", "")))
+	assert.True(t, filter(newWatchedOrg("b", "")))
+	assert.False(t, filter(newWatchedOrg("c", "")))
+	// b is still kept after it becomes invisible
+	visible["b"] = false
+	assert.True(t, filter(newWatchedOrg("b", "")))
+}
+
+func TestWatchPrinter(t *testing.T) {
+	p, err := cmdget.NewGetPrintFlags().ToPrinter()
+	assert.NoError(t, err)
+	out := &bytes.Buffer{}
+	list := &unstructured.UnstructuredList{Object: map[string]interface{}{"apiVersion": "v1", "kind": "List"}}
+	list.Items = append(list.Items, *newWatchedOrg("a", "1"))
+	assert.NoError(t, p.PrintObj(list, out))
+	assert.NoError(t, NewWatchPrinter(p, out).Handler()(watch.Modified, newWatchedOrg("b", "2")))
+	assert.Equal(t, 1, bytes.Count(out.Bytes(), []byte("NAME")))
+	assert.Contains(t, out.String(), "\nb ")
+}
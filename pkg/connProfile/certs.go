@@ -0,0 +1,254 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connProfile
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/relation"
+)
+
+// Cert is a certificate embedded in a connection profile
+type Cert struct {
+	// Source is where the certificate is in the profile, such as user/org1/alice or peer/org1-peer1
+	Source   string
+	Subject  string
+	Issuer   string
+	SANs     []string
+	NotAfter time.Time
+}
+
+// DaysLeft returns the number of days from now until c expires, negative if c is expired
+func (c Cert) DaysLeft(now time.Time) int {
+	return int(math.Floor(c.NotAfter.Sub(now).Hours() / 24))
+}
+
+// Certs returns all certificates in profile: the certificates of users, the signed certificates of organizations
+// and the TLS CA certificates of peers and orderers, sorted by their expiry.
+// Pem data which is not a certificate is skipped, invalid certificates are returned as errors.
+func Certs(profile *common.Profile) ([]Cert, []error) {
+	var (
+		certs []Cert
+		errs  []error
+	)
+	add := func(source string, data string) {
+		rest := []byte(data)
+		for {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				return
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid certificate in %s: %w", source, err))
+				continue
+			}
+			certs = append(certs, Cert{
+				Source:   source,
+				Subject:  cert.Subject.String(),
+				Issuer:   cert.Issuer.String(),
+				SANs:     sans(cert),
+				NotAfter: cert.NotAfter,
+			})
+		}
+	}
+	for orgName, org := range profile.Organizations {
+		add("organization/"+orgName, org.SignedCert.Pem)
+		for username, user := range org.Users {
+			add(fmt.Sprintf("user/%s/%s", orgName, username), user.Cert.Pem)
+		}
+	}
+	for name, peer := range profile.Peers {
+		add(TargetPeer+"/"+name, peer.TLSCACerts.Pem)
+	}
+	for name, orderer := range profile.Orderers {
+		add(TargetOrderer+"/"+name, orderer.TLSCACerts.Pem)
+	}
+	sort.Slice(certs, func(i, j int) bool {
+		if !certs[i].NotAfter.Equal(certs[j].NotAfter) {
+			return certs[i].NotAfter.Before(certs[j].NotAfter)
+		}
+		return certs[i].Source < certs[j].Source
+	})
+	return certs, errs
+}
+
+func sans(cert *x509.Certificate) []string {
+	result := append([]string(nil), cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		result = append(result, ip.String())
+	}
+	result = append(result, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		result = append(result, uri.String())
+	}
+	return result
+}
+
+// ParseDuration parses durations such as 30d besides those accepted by time.ParseDuration
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// PrintCerts prints certs as a table, days remaining are counted from now
+func PrintCerts(out io.Writer, certs []Cert, now time.Time) error {
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "SOURCE\tSUBJECT\tISSUER\tSANS\tNOT AFTER\tDAYS LEFT")
+	for _, c := range certs {
+		sans := strings.Join(c.SANs, ",")
+		if sans == "" {
+			sans = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\n", c.Source, c.Subject, c.Issuer, sans, c.NotAfter.UTC().Format(time.RFC3339), c.DaysLeft(now))
+	}
+	return w.Flush()
+}
+
+// CertsOptions are the options to report the certificates in the connection profile of a channel
+type CertsOptions struct {
+	Channel      string
+	Organization string
+	Username     string
+	// ExpiringWithin fails the report if any certificate expires within it, zero disables the check
+	ExpiringWithin time.Duration
+	// Now is the time to count days remaining from, the current time if zero
+	Now time.Time
+}
+
+// Run prints the certificates in the connection profile of o.Channel, it returns an error
+// if any certificate is invalid or expires within o.ExpiringWithin
+func (o *CertsOptions) Run(ctx context.Context, cli dynamic.Interface, out io.Writer, errOut io.Writer) error {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	channelDetail, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Channel}).Get(ctx, o.Channel, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	var userOrgs []string
+	if o.Organization == "" {
+		if userOrgs, err = org.ListUserOrganizations(cli, o.Username); err != nil {
+			return err
+		}
+	}
+	organization, err := DiscoverOrganization(o.Organization, userOrgs, relation.Members(channelDetail), o.Channel)
+	if err != nil {
+		return err
+	}
+	profile, err := GetProfile(ctx, cli, o.Channel, organization)
+	if err != nil {
+		return err
+	}
+
+	certs, errs := Certs(profile)
+	for _, err := range errs {
+		fmt.Fprintln(errOut, err)
+	}
+	if err = PrintCerts(out, certs, o.Now); err != nil {
+		return err
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("%d invalid certificates found", len(errs))
+	}
+	if o.ExpiringWithin == 0 {
+		return nil
+	}
+	expiring := 0
+	for _, c := range certs {
+		if c.NotAfter.Before(o.Now.Add(o.ExpiringWithin)) {
+			expiring++
+		}
+	}
+	if expiring != 0 {
+		return fmt.Errorf("%d certificates expire within %s", expiring, o.ExpiringWithin)
+	}
+	return nil
+}
+
+// NewGetCertsCmd returns the command to report the certificates in the connection profile of a channel
+func NewGetCertsCmd(option common.Options) *cobra.Command {
+	o := &CertsOptions{}
+	var expiringWithin string
+
+	cmd := &cobra.Command{
+		Use:   "certs --channel CHANNEL [--org ORG]",
+		Short: "Get the certificates in channel's connection profile with their expiry",
+		Long: `Get the certificates of users, organizations, peers and orderers in the connection profile of a channel,
+sorted by their expiry.
+
+With --expiring-within, the command fails if any certificate expires within the duration, which can be used by monitoring.
+The organization can be omitted if the user belongs to only one member of the channel.`,
+		Example: `  # list the certificates in the connection profile of channel ch1
+  bc-cli get certs --channel ch1 --org org1
+
+  # fail if any certificate expires within 30 days
+  bc-cli get certs --channel ch1 --org org1 --expiring-within 30d`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) (err error) {
+			o.Username = viper.GetString("auth.username")
+			if expiringWithin != "" {
+				o.ExpiringWithin, err = ParseDuration(expiringWithin)
+			}
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				return err
+			}
+			return o.Run(cmd.Context(), cli, option.Out, option.ErrOut)
+		},
+	}
+	cmd.Flags().StringVar(&o.Channel, "channel", "", "channel name")
+	cmd.Flags().StringVar(&o.Organization, "org", "", "organization name, required if the user belongs to more than one member of the channel")
+	cmd.Flags().StringVar(&expiringWithin, "expiring-within", "", "fail if any certificate expires within the duration, such as 30d or 72h")
+	_ = cmd.MarkFlagRequired("channel")
+	return cmd
+}
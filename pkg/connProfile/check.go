@@ -0,0 +1,371 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connProfile
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+// Statuses of checks
+const (
+	StatusOK      = "OK"
+	StatusWarning = "Warning"
+	StatusError   = "Error"
+)
+
+// Targets of checks
+const (
+	TargetPeer    = "peer"
+	TargetOrderer = "orderer"
+	TargetUser    = "user"
+)
+
+// ExpiryWarning is how long before their expiry certificates are reported as warnings
+const ExpiryWarning = 30 * 24 * time.Hour
+
+// Result is the result of checking a peer, an orderer or a user in a connection profile
+type Result struct {
+	Target string
+	Name   string
+	// URL is empty for users
+	URL    string
+	Status string
+	Detail string
+}
+
+// CheckOptions are the options to check a connection profile
+type CheckOptions struct {
+	// Timeout is the timeout of dialing each endpoint
+	Timeout time.Duration
+	// CAFile has the CA certificates of the organization MSP, which issue the certificates of users.
+	// Connection profiles do not have them, user certificates are only checked against the issuer
+	// of the organization's signed certificate if it is empty.
+	CAFile string
+	// Now is the time to check the expiry of certificates at, the current time if zero
+	Now time.Time
+}
+
+// LoadProfile reads a connection profile in any format written by get connProfile, except peer-env.
+// Profiles of the bestchains format are converted to a profile with the only peer and user.
+func LoadProfile(data []byte) (*common.Profile, error) {
+	var doc struct {
+		FabProfile *common.FabProfile `yaml:"fabProfile"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid connection profile: %w", err)
+	}
+	if doc.FabProfile != nil {
+		p := doc.FabProfile
+		peer := PeerKey(p.Organization, "peer")
+		return &common.Profile{
+			Client:   common.Client{Organization: p.Organization},
+			Channels: map[string]common.ChannelInfo{p.Channel: {Peers: map[string]common.PeerInfo{peer: {}}}},
+			Organizations: map[string]common.OrganizationInfo{p.Organization: {
+				Users: map[string]common.User{p.User.Name: p.User},
+				Peers: []string{peer},
+			}},
+			Peers: map[string]common.NodeEndpoint{peer: p.Enpoint},
+		}, nil
+	}
+
+	profile := &common.Profile{}
+	if err := yaml.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("invalid connection profile: %w", err)
+	}
+	if len(profile.Peers) == 0 && len(profile.Orderers) == 0 {
+		return nil, errors.New("invalid connection profile: no peer or orderer found")
+	}
+	return profile, nil
+}
+
+// Check dials all peers and orderers in profile with their TLS CA certificates, and validates
+// the certificates and keys of all users. Results are sorted by target and name.
+func Check(ctx context.Context, profile *common.Profile, options CheckOptions) []Result {
+	if options.Now.IsZero() {
+		options.Now = time.Now()
+	}
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []Result
+	)
+	add := func(r Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, r)
+	}
+	dial := func(target string, endpoints map[string]common.NodeEndpoint) {
+		for name, endpoint := range endpoints {
+			wg.Add(1)
+			go func(name string, endpoint common.NodeEndpoint) {
+				defer wg.Done()
+				add(checkEndpoint(ctx, target, name, endpoint, options))
+			}(name, endpoint)
+		}
+	}
+	dial(TargetPeer, profile.Peers)
+	dial(TargetOrderer, profile.Orderers)
+	wg.Wait()
+
+	var roots []byte
+	if options.CAFile != "" {
+		var err error
+		if roots, err = os.ReadFile(options.CAFile); err != nil {
+			add(Result{Target: TargetUser, Name: "*", Status: StatusError, Detail: fmt.Sprintf("read ca file: %s", err)})
+		}
+	}
+	for orgName, org := range profile.Organizations {
+		for username, user := range org.Users {
+			r := Result{Target: TargetUser, Name: orgName + "/" + username}
+			r.Status, r.Detail = checkUser(user, org, roots, options.Now)
+			results = append(results, r)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Target != results[j].Target {
+			return results[i].Target < results[j].Target
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// checkEndpoint completes a TLS handshake with endpoint, the server certificate must be issued by the TLS CA
+// in the profile for the host in the url. gRPC servers must negotiate HTTP/2.
+func checkEndpoint(ctx context.Context, target string, name string, endpoint common.NodeEndpoint, options CheckOptions) Result {
+	r := Result{Target: target, Name: name, URL: endpoint.URL, Status: StatusError}
+	if endpoint.URL == "" {
+		r.Detail = "no url"
+		return r
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(endpoint.TLSCACerts.Pem)) {
+		r.Detail = "no valid tls ca certificate"
+		return r
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+		ServerName: Host(endpoint.URL),
+		NextProtos: []string{"h2"},
+	}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	dialer := &tls.Dialer{Config: config}
+	conn, err := dialer.DialContext(ctx, "tcp", Address(endpoint.URL))
+	if err != nil {
+		r.Detail = err.Error()
+		return r
+	}
+	defer conn.Close()
+	latency := time.Since(start).Round(time.Millisecond)
+
+	state := conn.(*tls.Conn).ConnectionState()
+	if state.NegotiatedProtocol != "h2" {
+		r.Status = StatusWarning
+		r.Detail = fmt.Sprintf("http/2 is not negotiated, %s may not be a grpc server", Address(endpoint.URL))
+		return r
+	}
+	r.Status, r.Detail = checkExpiry(state.PeerCertificates[0], options.Now, "tls certificate")
+	r.Detail = fmt.Sprintf("%s, latency %s", r.Detail, latency)
+	return r
+}
+
+// checkUser validates the certificate of user, its private key and its issuer
+func checkUser(user common.User, org common.OrganizationInfo, roots []byte, now time.Time) (string, string) {
+	cert, err := parseCertificate([]byte(user.Cert.Pem))
+	if err != nil {
+		return StatusError, fmt.Sprintf("invalid certificate: %s", err)
+	}
+	if err = matchKey(cert, []byte(user.Key.Pem)); err != nil {
+		return StatusError, err.Error()
+	}
+
+	status, detail := checkExpiry(cert, now, "certificate")
+	if status == StatusError {
+		return status, detail
+	}
+	if len(roots) != 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(roots) {
+			return StatusError, "no valid certificate found in the ca file"
+		}
+		_, err := cert.Verify(x509.VerifyOptions{
+			Roots:       pool,
+			CurrentTime: now,
+			KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			return StatusError, fmt.Sprintf("certificate is not issued by the msp of organization: %s", err)
+		}
+	} else if signed, err := parseCertificate([]byte(org.SignedCert.Pem)); err == nil {
+		if !bytes.Equal(cert.RawIssuer, signed.RawIssuer) {
+			return StatusError, fmt.Sprintf("certificate is issued by %s, not the ca of organization %s", cert.Issuer, signed.Issuer)
+		}
+	}
+	return status, detail
+}
+
+// checkExpiry reports certificates which are expired, not valid yet or expire soon
+func checkExpiry(cert *x509.Certificate, now time.Time, what string) (string, string) {
+	switch {
+	case now.Before(cert.NotBefore):
+		return StatusError, fmt.Sprintf("%s is not valid until %s", what, cert.NotBefore.Format(time.RFC3339))
+	case now.After(cert.NotAfter):
+		return StatusError, fmt.Sprintf("%s expired at %s", what, cert.NotAfter.Format(time.RFC3339))
+	}
+	left := cert.NotAfter.Sub(now)
+	detail := fmt.Sprintf("%s expires in %d days", what, int(left.Hours()/24))
+	if left < ExpiryWarning {
+		return StatusWarning, detail
+	}
+	return StatusOK, detail
+}
+
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no pem data found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// matchKey returns an error if key is not the private key of cert
+func matchKey(cert *x509.Certificate, key []byte) error {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return errors.New("invalid private key: no pem data found")
+	}
+	var (
+		parsed interface{}
+		err    error
+	)
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid private key: %w", err)
+	}
+	var public crypto.PublicKey
+	switch k := parsed.(type) {
+	case *ecdsa.PrivateKey:
+		public = k.Public()
+	case *rsa.PrivateKey:
+		public = k.Public()
+	case ed25519.PrivateKey:
+		public = k.Public()
+	default:
+		return fmt.Errorf("unsupported private key %T", parsed)
+	}
+	if k, ok := public.(interface{ Equal(crypto.PublicKey) bool }); !ok || !k.Equal(cert.PublicKey) {
+		return errors.New("private key does not match the certificate")
+	}
+	return nil
+}
+
+// PrintResults prints results as a table
+func PrintResults(out io.Writer, results []Result) error {
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "TARGET\tNAME\tURL\tSTATUS\tDETAIL")
+	for _, r := range results {
+		url := r.URL
+		if url == "" {
+			url = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Target, r.Name, url, r.Status, r.Detail)
+	}
+	return w.Flush()
+}
+
+// NewCheckConnProfileCmd returns the command to check the endpoints and users of a connection profile
+func NewCheckConnProfileCmd(option common.Options) *cobra.Command {
+	options := CheckOptions{}
+	cmd := &cobra.Command{
+		Use:   "connProfile FILE",
+		Short: "Check the peers, orderers and users of a connection profile",
+		Long: `Check the peers, orderers and users of a connection profile saved by get connProfile.
+
+Each peer and orderer is dialed with the TLS CA certificates in the profile, and the certificate of
+each user is validated against its private key, the CA of the organization and its expiry.
+The command fails if any check fails.`,
+		Example: `  # check the connection profile of channel ch1
+  bc-cli check connProfile ~/.bestchains/connProfile/ch1.json
+
+  # check users against the CA certificates of the organization
+  bc-cli check connProfile ch1-fabric.yaml --ca-file org1-ca.pem`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			profile, err := LoadProfile(data)
+			if err != nil {
+				return err
+			}
+			results := Check(cmd.Context(), profile, options)
+			if err = PrintResults(option.Out, results); err != nil {
+				return err
+			}
+			failed := 0
+			for _, r := range results {
+				if r.Status == StatusError {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d checks failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().DurationVar(&options.Timeout, "timeout", 5*time.Second, "timeout of dialing each peer and orderer")
+	cmd.Flags().StringVar(&options.CAFile, "ca-file", "", "CA certificates of the organization msp which issue user certificates")
+	return cmd
+}
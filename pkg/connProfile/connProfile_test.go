@@ -0,0 +1,375 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connProfile
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/bestchains/bc-cli/pkg/channel"
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+func TestValidate(t *testing.T) {
+	o := Options{Channel: "ch1", Username: "alice", Output: "json", Format: FormatBestchains}
+	assert.NoError(t, o.Validate())
+
+	invalid := o
+	invalid.Output = "xml"
+	assert.ErrorContains(t, invalid.Validate(), `invalid output "xml"`)
+	invalid = o
+	invalid.Format = "ccp"
+	assert.ErrorContains(t, invalid.Validate(), `invalid format "ccp"`)
+	invalid = o
+	invalid.Username = ""
+	assert.ErrorContains(t, invalid.Validate(), "no user logged in")
+	invalid = o
+	invalid.Format, invalid.Stdout = FormatPeerEnv, true
+	assert.ErrorContains(t, invalid.Validate(), "--stdout is not supported")
+}
+
+func TestDiscover(t *testing.T) {
+	members := []string{"org1", "org2"}
+	org, err := DiscoverOrganization("", []string{"org1", "org3"}, members, "ch1")
+	assert.NoError(t, err)
+	assert.Equal(t, "org1", org)
+	_, err = DiscoverOrganization("", []string{"org1", "org2"}, members, "ch1")
+	assert.ErrorContains(t, err, "specify one by --org")
+	_, err = DiscoverOrganization("", []string{"org3"}, members, "ch1")
+	assert.ErrorContains(t, err, "none of your organizations")
+	_, err = DiscoverOrganization("org3", nil, members, "ch1")
+	assert.ErrorContains(t, err, "not a member of channel ch1")
+
+	assert.Equal(t, []string{"peer1"}, Peers(newProfile(), "org1"))
+	peer, err := DiscoverPeer("", []string{"peer1"}, "org1")
+	assert.NoError(t, err)
+	assert.Equal(t, "peer1", peer)
+	_, err = DiscoverPeer("", []string{"peer1", "peer2"}, "org1")
+	assert.ErrorContains(t, err, "specify one by --peer")
+	_, err = DiscoverPeer("peer3", []string{"peer1", "peer2"}, "org1")
+	assert.ErrorContains(t, err, "peers: peer1,peer2")
+	_, err = DiscoverPeer("", nil, "org1")
+	assert.ErrorContains(t, err, "has no peer")
+}
+
+func newFakeClient(t *testing.T, profile *common.Profile) *fake.FakeDynamicClient {
+	raw, err := json.Marshal(profile)
+	assert.NoError(t, err)
+	ch := channel.NewChannel("ch1", "net1", "org1", []string{"org2"}, nil, "")
+	assert.NoError(t, unstructured.SetNestedField(ch.Object, "ch1", "spec", "id"))
+	configmap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "chan-ch1-connection-profile", "namespace": "org1"},
+		"binaryData": map[string]interface{}{"profile.json": base64.StdEncoding.EncodeToString(raw)},
+	}}
+	return fake.NewSimpleDynamicClient(runtime.NewScheme(), ch, configmap)
+}
+
+func TestRun(t *testing.T) {
+	cli := newFakeClient(t, newProfile())
+	ctx := context.Background()
+	dir := t.TempDir()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	o := Options{Channel: "ch1", Organization: "org1", Username: "alice", Output: "json", Format: FormatBestchains, Dir: dir}
+
+	// the only peer is discovered
+	assert.NoError(t, o.Run(ctx, cli, out, errOut))
+	assert.Contains(t, errOut.String(), "using peer peer1")
+	raw, err := os.ReadFile(filepath.Join(dir, "ch1.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(raw), "org1-peer1.example.com")
+
+	// existing files are not overwritten without --force
+	assert.ErrorContains(t, o.Run(ctx, cli, out, errOut), "use --force to overwrite it")
+	o.Force = true
+	assert.NoError(t, o.Run(ctx, cli, out, errOut))
+
+	out.Reset()
+	stdout := o
+	stdout.Stdout, stdout.Format, stdout.Output = true, FormatFabric, "yaml"
+	assert.NoError(t, stdout.Run(ctx, cli, out, errOut))
+	assert.Contains(t, out.String(), "organization: org1")
+	assert.NotContains(t, out.String(), "org2-peer1")
+
+	out.Reset()
+	list := o
+	list.ListPeers = true
+	assert.NoError(t, list.Run(ctx, cli, out, errOut))
+	assert.Equal(t, "PEER    URL\npeer1   grpcs://org1-peer1.example.com:7051\n", out.String())
+
+	invalid := o
+	invalid.Username = "bob"
+	assert.ErrorContains(t, invalid.Run(ctx, cli, out, errOut), "user bob not found in organization org1")
+	invalid = o
+	invalid.Organization = "org2"
+	assert.ErrorContains(t, invalid.Run(ctx, cli, out, errOut), "not found")
+	invalid = o
+	invalid.Channel = "ch2"
+	assert.ErrorContains(t, invalid.Run(ctx, cli, out, errOut), "not found")
+
+	peerEnv := o
+	peerEnv.Format = FormatPeerEnv
+	assert.NoError(t, peerEnv.Run(ctx, cli, out, errOut))
+	env, err := os.ReadFile(filepath.Join(dir, "ch1-org1-peer1", PeerEnvFile))
+	assert.NoError(t, err)
+	assert.Contains(t, string(env), `CORE_PEER_LOCALMSPID="org1"`)
+	info, err := os.Stat(filepath.Join(dir, "ch1-org1-peer1", "msp", "keystore", "key.pem"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// issue returns the certificate and key in pem issued by ca, valid until notAfter
+func (ca *testCA) issue(t *testing.T, name string, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+// listen starts a TLS listener which stands in for a grpc server, it returns the url of the listener
+func listen(t *testing.T, ca *testCA, protos []string) string {
+	certPEM, keyPEM := ca.issue(t, "server", time.Now().Add(100*24*time.Hour))
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	assert.NoError(t, err)
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}, NextProtos: protos})
+	assert.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+	return "grpcs://" + l.Addr().String()
+}
+
+func TestCheck(t *testing.T) {
+	tlsCA, otherCA, mspCA := newCA(t, "tlsca"), newCA(t, "other"), newCA(t, "ca")
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closedURL := "grpcs://" + closed.Addr().String()
+	closed.Close()
+
+	aliceCert, aliceKey := mspCA.issue(t, "alice", time.Now().Add(100*24*time.Hour))
+	bobCert, bobKey := mspCA.issue(t, "bob", time.Now().Add(10*24*time.Hour))
+	carolCert, _ := mspCA.issue(t, "carol", time.Now().Add(100*24*time.Hour))
+	daveCert, daveKey := otherCA.issue(t, "dave", time.Now().Add(100*24*time.Hour))
+	adminCert, _ := mspCA.issue(t, "admin", time.Now().Add(100*24*time.Hour))
+	profile := &common.Profile{
+		Organizations: map[string]common.OrganizationInfo{"org1": {
+			MSPID:      "org1",
+			SignedCert: common.Pem{Pem: adminCert},
+			Users: map[string]common.User{
+				"alice": {Cert: common.Pem{Pem: aliceCert}, Key: common.Pem{Pem: aliceKey}},
+				"bob":   {Cert: common.Pem{Pem: bobCert}, Key: common.Pem{Pem: bobKey}},
+				"carol": {Cert: common.Pem{Pem: carolCert}, Key: common.Pem{Pem: aliceKey}},
+				"dave":  {Cert: common.Pem{Pem: daveCert}, Key: common.Pem{Pem: daveKey}},
+			},
+		}},
+		Peers: map[string]common.NodeEndpoint{
+			"org1-peer1": {URL: listen(t, tlsCA, []string{"h2"}), TLSCACerts: common.TLSCACerts{Pem: tlsCA.pem}},
+			"org1-peer2": {URL: listen(t, tlsCA, []string{"h2"}), TLSCACerts: common.TLSCACerts{Pem: otherCA.pem}},
+			"org1-peer3": {URL: closedURL, TLSCACerts: common.TLSCACerts{Pem: tlsCA.pem}},
+		},
+		Orderers: map[string]common.NodeEndpoint{
+			"orderer1": {URL: listen(t, tlsCA, nil), TLSCACerts: common.TLSCACerts{Pem: tlsCA.pem}},
+		},
+	}
+
+	results := Check(context.Background(), profile, CheckOptions{Timeout: 5 * time.Second})
+	statuses := map[string]string{}
+	for _, r := range results {
+		statuses[r.Target+"/"+r.Name] = r.Status
+	}
+	assert.Equal(t, map[string]string{
+		"orderer/orderer1": StatusWarning,
+		"peer/org1-peer1":  StatusOK,
+		"peer/org1-peer2":  StatusError,
+		"peer/org1-peer3":  StatusError,
+		"user/org1/alice":  StatusOK,
+		"user/org1/bob":    StatusWarning,
+		"user/org1/carol":  StatusError,
+		"user/org1/dave":   StatusError,
+	}, statuses)
+	assert.Equal(t, TargetOrderer, results[0].Target)
+	assert.Contains(t, results[1].Detail, "tls certificate expires in 99 days")
+
+	// users are checked against the ca file, and expired certificates fail
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NoError(t, os.WriteFile(caFile, []byte(otherCA.pem), 0600))
+	results = Check(context.Background(), &common.Profile{Organizations: profile.Organizations}, CheckOptions{CAFile: caFile, Now: time.Now().Add(50 * 24 * time.Hour)})
+	details := map[string]string{}
+	for _, r := range results {
+		details[r.Name] = r.Status + ": " + r.Detail
+	}
+	assert.Contains(t, details["org1/alice"], "Error: certificate is not issued by the msp of organization")
+	assert.Contains(t, details["org1/bob"], "Error: certificate expired at")
+	assert.Contains(t, details["org1/carol"], "private key does not match the certificate")
+	assert.Equal(t, "OK: certificate expires in 49 days", details["org1/dave"])
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, PrintResults(out, results[:1]))
+	assert.Contains(t, out.String(), "TARGET   NAME         URL   STATUS")
+}
+
+func TestLoadProfile(t *testing.T) {
+	raw, err := json.Marshal(map[string]interface{}{
+		"id":       "net1",
+		"platform": "bestchains",
+		"fabProfile": common.FabProfile{
+			Channel:      "ch1",
+			Organization: "org1",
+			User:         common.User{Name: "alice"},
+			Enpoint:      common.NodeEndpoint{URL: "grpcs://peer1.example.com"},
+		},
+	})
+	assert.NoError(t, err)
+	profile, err := LoadProfile(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "grpcs://peer1.example.com", profile.Peers["org1-peer"].URL)
+	assert.Contains(t, profile.Organizations["org1"].Users, "alice")
+
+	config, err := NewSDKConfig(newProfile(), "ch1", "org1", "alice")
+	assert.NoError(t, err)
+	data, err := yaml.Marshal(config)
+	assert.NoError(t, err)
+	profile, err = LoadProfile(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "org1-ca", profile.Peers["org1-peer1"].TLSCACerts.Pem)
+	assert.Equal(t, "cert", profile.Organizations["org1"].Users["alice"].Cert.Pem)
+
+	_, err = LoadProfile([]byte("version: 1.0.0"))
+	assert.ErrorContains(t, err, "no peer or orderer found")
+}
+
+func TestParseDuration(t *testing.T) {
+	d, err := ParseDuration("30d")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, d)
+	d, err = ParseDuration("72h")
+	assert.NoError(t, err)
+	assert.Equal(t, 72*time.Hour, d)
+	for _, s := range []string{"d", "-1d", "1w", "-1h"} {
+		_, err = ParseDuration(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCerts(t *testing.T) {
+	tlsCA, mspCA := newCA(t, "tlsca"), newCA(t, "ca")
+	aliceCert, _ := mspCA.issue(t, "alice", time.Now().Add(10*24*time.Hour))
+	adminCert, _ := mspCA.issue(t, "admin", time.Now().Add(100*24*time.Hour))
+	profile := newProfile()
+	profile.Organizations["org1"] = common.OrganizationInfo{
+		MSPID:      "org1",
+		SignedCert: common.Pem{Pem: adminCert},
+		Users:      map[string]common.User{"alice": {Cert: common.Pem{Pem: aliceCert}}},
+		Peers:      []string{"org1-peer1"},
+	}
+	profile.Peers["org1-peer1"] = common.NodeEndpoint{TLSCACerts: common.TLSCACerts{Pem: tlsCA.pem + mspCA.pem}}
+	profile.Peers["org2-peer1"] = common.NodeEndpoint{TLSCACerts: common.TLSCACerts{Pem: "-----BEGIN CERTIFICATE-----\naW52YWxpZA==\n-----END CERTIFICATE-----\n"}}
+
+	certs, errs := Certs(profile)
+	assert.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "invalid certificate in peer/org2-peer1")
+	sources := make([]string, 0, len(certs))
+	for _, c := range certs {
+		sources = append(sources, c.Source)
+	}
+	assert.Equal(t, []string{"user/org1/alice", "organization/org1", "peer/org1-peer1", "peer/org1-peer1"}, sources)
+	assert.Equal(t, "CN=alice", certs[0].Subject)
+	assert.Equal(t, "CN=ca", certs[0].Issuer)
+	assert.Equal(t, []string{"127.0.0.1"}, certs[0].SANs)
+	assert.Equal(t, 9, certs[0].DaysLeft(time.Now()))
+
+	delete(profile.Peers, "org2-peer1")
+	cli := newFakeClient(t, profile)
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	o := CertsOptions{Channel: "ch1", Organization: "org1"}
+	assert.NoError(t, o.Run(context.Background(), cli, out, errOut))
+	assert.Regexp(t, `^SOURCE +SUBJECT +ISSUER +SANS +NOT AFTER +DAYS LEFT\n`, out.String())
+	assert.Regexp(t, `\nuser/org1/alice +CN=alice +CN=ca +127\.0\.0\.1 +\S+ +9\n`, out.String())
+	assert.Regexp(t, `\npeer/org1-peer1 +CN=ca +CN=ca +<none> +`, out.String())
+
+	o.ExpiringWithin = 30 * 24 * time.Hour
+	assert.ErrorContains(t, o.Run(context.Background(), cli, out, errOut), "1 certificates expire within 720h0m0s")
+	o.ExpiringWithin = 24 * time.Hour
+	assert.NoError(t, o.Run(context.Background(), cli, out, errOut))
+}
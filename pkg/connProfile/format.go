@@ -0,0 +1,285 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connProfile
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+// Formats of connection profiles
+const (
+	// FormatBestchains is the document with the channel, organization, user and a single peer used by bestchains tools
+	FormatBestchains = "bestchains"
+	// FormatFabric is the common connection profile of Fabric SDKs and gateways, filtered to one organization
+	FormatFabric = "fabric"
+	// FormatSDKGo is the config of fabric-sdk-go
+	FormatSDKGo = "sdk-go"
+	// FormatPeerEnv is a directory with the MSP and TLS certificates of the user,
+	// along with the environment variables of the peer CLI which refer to them
+	FormatPeerEnv = "peer-env"
+)
+
+// Formats are all supported formats
+var Formats = []string{FormatBestchains, FormatFabric, FormatSDKGo, FormatPeerEnv}
+
+// PeerKey returns the key of peer of organization in Profile.Peers
+func PeerKey(organization string, peer string) string {
+	return fmt.Sprintf("%s-%s", organization, peer)
+}
+
+// FilterProfile returns the connection profile of username in organization in channel, which only has the organization
+// with the user, its peers and the orderers. The keys of other users and the admin are left out,
+// the profile of the whole channel is not changed.
+func FilterProfile(profile *common.Profile, channel string, organization string, username string) (*common.Profile, error) {
+	org, ok := profile.Organizations[organization]
+	if !ok {
+		return nil, fmt.Errorf("organization %s not found in the connection profile of channel %s", organization, channel)
+	}
+	user, ok := org.Users[username]
+	if !ok {
+		return nil, fmt.Errorf("user %s not found in organization %s", username, organization)
+	}
+	org.Users = map[string]common.User{username: user}
+	org.AdminPrivateKey = common.Pem{}
+	channelInfo, ok := profile.Channels[channel]
+	if !ok {
+		return nil, fmt.Errorf("channel %s not found in the connection profile", channel)
+	}
+
+	filtered := &common.Profile{
+		Version:       profile.Version,
+		Client:        profile.Client,
+		Channels:      map[string]common.ChannelInfo{channel: {Peers: map[string]common.PeerInfo{}}},
+		Organizations: map[string]common.OrganizationInfo{organization: org},
+		Orderers:      profile.Orderers,
+		Peers:         map[string]common.NodeEndpoint{},
+	}
+	filtered.Client.Organization = organization
+	for _, peer := range org.Peers {
+		if endpoint, ok := profile.Peers[peer]; ok {
+			filtered.Peers[peer] = endpoint
+		}
+		if info, ok := channelInfo.Peers[peer]; ok {
+			filtered.Channels[channel].Peers[peer] = info
+		}
+	}
+	return filtered, nil
+}
+
+// SDKConfig is the config of fabric-sdk-go, see
+// https://github.com/hyperledger/fabric-sdk-go/blob/main/pkg/core/config/testdata/template/config.yaml
+type SDKConfig struct {
+	Version       string                     `yaml:"version" json:"version"`
+	Client        SDKClient                  `yaml:"client" json:"client"`
+	Channels      map[string]SDKChannel      `yaml:"channels" json:"channels"`
+	Organizations map[string]SDKOrganization `yaml:"organizations" json:"organizations"`
+	Orderers      map[string]SDKEndpoint     `yaml:"orderers" json:"orderers"`
+	Peers         map[string]SDKEndpoint     `yaml:"peers" json:"peers"`
+}
+
+type SDKClient struct {
+	Organization    string                 `yaml:"organization" json:"organization"`
+	Logging         common.Logging         `yaml:"logging" json:"logging"`
+	CryptoConfig    common.CryptoConfig    `yaml:"cryptoconfig" json:"cryptoconfig"`
+	CredentialStore common.CredentialStore `yaml:"credentialStore" json:"credentialStore"`
+}
+
+type SDKChannel struct {
+	Orderers []string                   `yaml:"orderers,omitempty" json:"orderers,omitempty"`
+	Peers    map[string]common.PeerInfo `yaml:"peers" json:"peers"`
+}
+
+type SDKOrganization struct {
+	MSPID      string                 `yaml:"mspid" json:"mspid"`
+	CryptoPath string                 `yaml:"cryptoPath" json:"cryptoPath"`
+	Users      map[string]common.User `yaml:"users,omitempty" json:"users,omitempty"`
+	Peers      []string               `yaml:"peers,omitempty" json:"peers,omitempty"`
+}
+
+type SDKEndpoint struct {
+	URL         string                 `yaml:"url" json:"url"`
+	GRPCOptions map[string]interface{} `yaml:"grpcOptions,omitempty" json:"grpcOptions,omitempty"`
+	TLSCACerts  common.TLSCACerts      `yaml:"tlsCACerts" json:"tlsCACerts"`
+}
+
+// NewSDKConfig returns the fabric-sdk-go config of username in organization in channel, the user is embedded in the organization
+func NewSDKConfig(profile *common.Profile, channel string, organization string, username string) (*SDKConfig, error) {
+	filtered, err := FilterProfile(profile, channel, organization, username)
+	if err != nil {
+		return nil, err
+	}
+	org := filtered.Organizations[organization]
+	level := filtered.Client.Logging.Level
+	if level == "" {
+		level = "info"
+	}
+	config := &SDKConfig{
+		Version: "1.0.0",
+		Client: SDKClient{
+			Organization: organization,
+			Logging:      common.Logging{Level: level},
+			// users are embedded, the paths are required by fabric-sdk-go but not read
+			CryptoConfig: common.CryptoConfig{Path: "/tmp/msp"},
+			CredentialStore: common.CredentialStore{
+				Path:        "/tmp/state-store",
+				CryptoStore: common.CryptoStore{Path: "/tmp/msp"},
+			},
+		},
+		Channels: map[string]SDKChannel{channel: {
+			Orderers: sortedKeys(filtered.Orderers),
+			Peers:    filtered.Channels[channel].Peers,
+		}},
+		Organizations: map[string]SDKOrganization{organization: {
+			MSPID:      org.MSPID,
+			CryptoPath: "peerOrganizations/" + organization + "/users/{username}/msp",
+			Users:      org.Users,
+			Peers:      org.Peers,
+		}},
+		Orderers: map[string]SDKEndpoint{},
+		Peers:    map[string]SDKEndpoint{},
+	}
+	for name, endpoint := range filtered.Orderers {
+		config.Orderers[name] = newSDKEndpoint(endpoint)
+	}
+	for name, endpoint := range filtered.Peers {
+		config.Peers[name] = newSDKEndpoint(endpoint)
+	}
+	return config, nil
+}
+
+func newSDKEndpoint(endpoint common.NodeEndpoint) SDKEndpoint {
+	e := SDKEndpoint{URL: endpoint.URL, TLSCACerts: endpoint.TLSCACerts}
+	if host := Host(endpoint.URL); host != "" {
+		e.GRPCOptions = map[string]interface{}{
+			"ssl-target-name-override": host,
+			"keep-alive-time":          "0s",
+			"keep-alive-timeout":       "20s",
+			"keep-alive-permit":        false,
+			"fail-fast":                false,
+			"allow-insecure":           false,
+		}
+	}
+	return e
+}
+
+// Host returns the host of a grpc(s) url such as grpcs://peer0.example.com:443, the url can omit the scheme
+func Host(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "grpcs://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// Address returns the host:port of a grpc(s) url, as expected by the peer CLI
+func Address(rawURL string) string {
+	if !strings.Contains(rawURL, "://") {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.Port() == "" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return u.Host
+}
+
+// PeerEnv is the MSP and TLS material of a user and the environment variables of the peer CLI
+type PeerEnv struct {
+	// Files are the contents of files by paths relative to the directory of the environment
+	Files map[string][]byte
+	// Env are the lines of the environment file which export variables
+	Env []string
+}
+
+// Files of the peer CLI environment
+const (
+	PeerEnvFile     = "peer.env"
+	peerSignCert    = "msp/signcerts/cert.pem"
+	peerKey         = "msp/keystore/key.pem"
+	peerTLSRootCert = "tls/peer-ca.pem"
+	ordererTLSCert  = "tls/orderer-ca.pem"
+)
+
+// NewPeerEnv returns the environment of the peer CLI which connects to peer of organization as user,
+// files are referred to under dir. The first orderer is used by commands such as peer chaincode invoke.
+// The CA certificates of the organization are not in connection profiles, msp/cacerts is left for users to fill in.
+func NewPeerEnv(profile *common.Profile, channel string, organization string, peer string, username string, dir string) (*PeerEnv, error) {
+	org, ok := profile.Organizations[organization]
+	if !ok {
+		return nil, fmt.Errorf("organization %s not found in the connection profile of channel %s", organization, channel)
+	}
+	user, ok := org.Users[username]
+	if !ok {
+		return nil, fmt.Errorf("user %s not found in organization %s", username, organization)
+	}
+	endpoint, ok := profile.Peers[PeerKey(organization, peer)]
+	if !ok {
+		return nil, fmt.Errorf("peer %s not found in organization %s", peer, organization)
+	}
+
+	env := &PeerEnv{
+		Files: map[string][]byte{
+			peerSignCert:    []byte(user.Cert.Pem),
+			peerKey:         []byte(user.Key.Pem),
+			peerTLSRootCert: []byte(endpoint.TLSCACerts.Pem),
+		},
+	}
+	export := func(key string, value string) {
+		env.Env = append(env.Env, fmt.Sprintf("export %s=%q", key, value))
+	}
+	export("CORE_PEER_LOCALMSPID", org.MSPID)
+	export("CORE_PEER_MSPCONFIGPATH", filepath.Join(dir, "msp"))
+	export("CORE_PEER_ADDRESS", Address(endpoint.URL))
+	export("CORE_PEER_TLS_ENABLED", "true")
+	export("CORE_PEER_TLS_ROOTCERT_FILE", filepath.Join(dir, peerTLSRootCert))
+	if host := Host(endpoint.URL); host != "" {
+		export("CORE_PEER_TLS_SERVERHOSTOVERRIDE", host)
+	}
+	export("CHANNEL_NAME", channel)
+	if orderers := sortedKeys(profile.Orderers); len(orderers) > 0 {
+		orderer := profile.Orderers[orderers[0]]
+		env.Files[ordererTLSCert] = []byte(orderer.TLSCACerts.Pem)
+		export("ORDERER_ADDRESS", Address(orderer.URL))
+		export("ORDERER_CA", filepath.Join(dir, ordererTLSCert))
+		if host := Host(orderer.URL); host != "" {
+			export("ORDERER_TLS_HOSTNAME_OVERRIDE", host)
+		}
+	}
+	return env, nil
+}
+
+func sortedKeys(endpoints map[string]common.NodeEndpoint) []string {
+	keys := make([]string, 0, len(endpoints))
+	for key := range endpoints {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connProfile
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+func newProfile() *common.Profile {
+	return &common.Profile{
+		Version: "1.0.0",
+		Channels: map[string]common.ChannelInfo{
+			"ch1": {Peers: map[string]common.PeerInfo{"org1-peer1": {}, "org2-peer1": {}}},
+		},
+		Organizations: map[string]common.OrganizationInfo{
+			"org1": {
+				MSPID: "org1",
+				Users: map[string]common.User{
+					"alice": {Name: "alice", Key: common.Pem{Pem: "key"}, Cert: common.Pem{Pem: "cert"}},
+					"carol": {Name: "carol", Key: common.Pem{Pem: "carol-key"}, Cert: common.Pem{Pem: "carol-cert"}},
+				},
+				Peers:           []string{"org1-peer1"},
+				AdminPrivateKey: common.Pem{Pem: "admin-key"},
+			},
+			"org2": {MSPID: "org2", Peers: []string{"org2-peer1"}},
+		},
+		Orderers: map[string]common.NodeEndpoint{
+			"orderer1": {URL: "grpcs://orderer1.example.com", TLSCACerts: common.TLSCACerts{Pem: "orderer-ca"}},
+		},
+		Peers: map[string]common.NodeEndpoint{
+			"org1-peer1": {URL: "grpcs://org1-peer1.example.com:7051", TLSCACerts: common.TLSCACerts{Pem: "org1-ca"}},
+			"org2-peer1": {URL: "org2-peer1.example.com:7051", TLSCACerts: common.TLSCACerts{Pem: "org2-ca"}},
+		},
+	}
+}
+
+func TestFilterProfile(t *testing.T) {
+	profile := newProfile()
+	filtered, err := FilterProfile(profile, "ch1", "org1", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "org1", filtered.Client.Organization)
+	assert.Len(t, filtered.Organizations, 1)
+	// only the keys of the user are kept
+	assert.Equal(t, []string{"alice"}, sortedUsers(filtered.Organizations["org1"].Users))
+	assert.Empty(t, filtered.Organizations["org1"].AdminPrivateKey.Pem)
+	assert.Len(t, profile.Organizations["org1"].Users, 2)
+	assert.Equal(t, "admin-key", profile.Organizations["org1"].AdminPrivateKey.Pem)
+	assert.Contains(t, filtered.Peers, "org1-peer1")
+	assert.NotContains(t, filtered.Peers, "org2-peer1")
+	assert.Len(t, filtered.Channels["ch1"].Peers, 1)
+	assert.Len(t, filtered.Orderers, 1)
+	// the original profile is not changed
+	assert.Len(t, profile.Channels["ch1"].Peers, 2)
+
+	_, err = FilterProfile(profile, "ch1", "org3", "alice")
+	assert.ErrorContains(t, err, "organization org3 not found")
+	_, err = FilterProfile(profile, "ch2", "org1", "alice")
+	assert.ErrorContains(t, err, "channel ch2 not found")
+	_, err = FilterProfile(profile, "ch1", "org1", "bob")
+	assert.ErrorContains(t, err, "user bob not found")
+}
+
+func sortedUsers(users map[string]common.User) []string {
+	names := make([]string, 0, len(users))
+	for name := range users {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestNewSDKConfig(t *testing.T) {
+	config, err := NewSDKConfig(newProfile(), "ch1", "org1", "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "org1", config.Client.Organization)
+	assert.Equal(t, []string{"orderer1"}, config.Channels["ch1"].Orderers)
+	assert.Equal(t, "cert", config.Organizations["org1"].Users["alice"].Cert.Pem)
+	assert.NotContains(t, config.Organizations["org1"].Users, "carol")
+	peer := config.Peers["org1-peer1"]
+	assert.Equal(t, "org1-peer1.example.com", peer.GRPCOptions["ssl-target-name-override"])
+	assert.Equal(t, "org1-ca", peer.TLSCACerts.Pem)
+	assert.NotContains(t, config.Peers, "org2-peer1")
+}
+
+func TestNewPeerEnv(t *testing.T) {
+	env, err := NewPeerEnv(newProfile(), "ch1", "org1", "peer1", "alice", "/env")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`export CORE_PEER_LOCALMSPID="org1"`,
+		`export CORE_PEER_MSPCONFIGPATH="/env/msp"`,
+		`export CORE_PEER_ADDRESS="org1-peer1.example.com:7051"`,
+		`export CORE_PEER_TLS_ENABLED="true"`,
+		`export CORE_PEER_TLS_ROOTCERT_FILE="/env/tls/peer-ca.pem"`,
+		`export CORE_PEER_TLS_SERVERHOSTOVERRIDE="org1-peer1.example.com"`,
+		`export CHANNEL_NAME="ch1"`,
+		`export ORDERER_ADDRESS="orderer1.example.com:443"`,
+		`export ORDERER_CA="/env/tls/orderer-ca.pem"`,
+		`export ORDERER_TLS_HOSTNAME_OVERRIDE="orderer1.example.com"`,
+	}, env.Env)
+	assert.Equal(t, "key", string(env.Files["msp/keystore/key.pem"]))
+	assert.Equal(t, "cert", string(env.Files["msp/signcerts/cert.pem"]))
+	assert.Equal(t, "org1-ca", string(env.Files["tls/peer-ca.pem"]))
+
+	_, err = NewPeerEnv(newProfile(), "ch1", "org1", "peer1", "bob", "/env")
+	assert.ErrorContains(t, err, "user bob not found")
+	_, err = NewPeerEnv(newProfile(), "ch1", "org1", "peer2", "alice", "/env")
+	assert.ErrorContains(t, err, "peer peer2 not found")
+}
+
+func TestAddress(t *testing.T) {
+	assert.Equal(t, "peer.example.com:7051", Address("peer.example.com:7051"))
+	assert.Equal(t, "peer.example.com:7051", Address("grpcs://peer.example.com:7051"))
+	assert.Equal(t, "peer.example.com:443", Address("grpcs://peer.example.com"))
+	assert.Equal(t, "peer.example.com", Host("peer.example.com:7051"))
+}
@@ -21,7 +21,10 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -29,116 +32,360 @@ import (
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/relation"
 	"github.com/bestchains/bc-cli/pkg/utils"
 )
 
+// Options are the options to get the connection profile of a channel
+type Options struct {
+	Channel      string
+	Organization string
+	Peer         string
+	// Username is the user whose identity is put into the connection profile
+	Username string
+	Output   string
+	Format   string
+	Dir      string
+	// Stdout prints the connection profile instead of saving it into Dir
+	Stdout bool
+	// Force overwrites existing files
+	Force bool
+	// ListPeers lists the peers of the organization instead of getting the connection profile
+	ListPeers bool
+}
+
+// Validate checks the options which do not depend on the channel
+func (o *Options) Validate() error {
+	if o.Channel == "" {
+		return fmt.Errorf("--channel is required")
+	}
+	if o.Username == "" {
+		return fmt.Errorf("no user logged in")
+	}
+	if o.Output != "json" && o.Output != "yaml" {
+		return fmt.Errorf("invalid output %q, must be json or yaml", o.Output)
+	}
+	if !utils.ContainsString(Formats, o.Format) {
+		return fmt.Errorf("invalid format %q, supported formats: %s", o.Format, strings.Join(Formats, ","))
+	}
+	if o.Stdout && o.Format == FormatPeerEnv {
+		return fmt.Errorf("--stdout is not supported by format %s which writes a directory", FormatPeerEnv)
+	}
+	return nil
+}
+
+// DiscoverOrganization returns organization if it is set, otherwise the only organization
+// of the user which is a member of the channel
+func DiscoverOrganization(organization string, userOrgs []string, members []string, channel string) (string, error) {
+	if organization != "" {
+		if !utils.ContainsString(members, organization) {
+			return "", fmt.Errorf("organization %s is not a member of channel %s, members: %s", organization, channel, strings.Join(members, ","))
+		}
+		return organization, nil
+	}
+	var candidates []string
+	for _, o := range userOrgs {
+		if utils.ContainsString(members, o) {
+			candidates = append(candidates, o)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return "", fmt.Errorf("none of your organizations is a member of channel %s, members: %s", channel, strings.Join(members, ","))
+	case 1:
+		return candidates[0], nil
+	}
+	return "", fmt.Errorf("you belong to organizations %s of channel %s, specify one by --org", strings.Join(candidates, ","), channel)
+}
+
+// Peers returns the names of peers of organization in profile, sorted
+func Peers(profile *common.Profile, organization string) []string {
+	prefix := PeerKey(organization, "")
+	var peers []string
+	for _, key := range profile.Organizations[organization].Peers {
+		if name := strings.TrimPrefix(key, prefix); name != key && name != "" {
+			peers = append(peers, name)
+		}
+	}
+	sort.Strings(peers)
+	return peers
+}
+
+// DiscoverPeer returns peer if it is one of peers, otherwise the only one of peers if peer is not set
+func DiscoverPeer(peer string, peers []string, organization string) (string, error) {
+	if len(peers) == 0 {
+		return "", fmt.Errorf("organization %s has no peer in the channel", organization)
+	}
+	if peer != "" {
+		if !utils.ContainsString(peers, peer) {
+			return "", fmt.Errorf("peer %s of organization %s is not in the channel, peers: %s", peer, organization, strings.Join(peers, ","))
+		}
+		return peer, nil
+	}
+	if len(peers) == 1 {
+		return peers[0], nil
+	}
+	return "", fmt.Errorf("organization %s has peers %s in the channel, specify one by --peer", organization, strings.Join(peers, ","))
+}
+
+// GetProfile returns the connection profile of channel in the namespace of organization
+func GetProfile(ctx context.Context, cli dynamic.Interface, channel string, organization string) (*common.Profile, error) {
+	configmapName := fmt.Sprintf("chan-%s-connection-profile", channel)
+	configmap, err := cli.Resource(schema.GroupVersionResource{Version: common.CoreVersion, Resource: common.Configmap}).Namespace(organization).Get(ctx, configmapName, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	profileJson := utils.GetNestedString(configmap.Object, "binaryData", "profile.json")
+	if profileJson == "" {
+		return nil, fmt.Errorf("connection profile of channel %s is not ready in organization %s", channel, organization)
+	}
+	rawProfileJson, err := base64.StdEncoding.DecodeString(profileJson)
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection profile of channel %s: %w", channel, err)
+	}
+	var profile *common.Profile
+	if err = json.Unmarshal(rawProfileJson, &profile); err != nil {
+		return nil, fmt.Errorf("invalid connection profile of channel %s: %w", channel, err)
+	}
+	if profile == nil {
+		return nil, fmt.Errorf("connection profile of channel %s is empty", channel)
+	}
+	return profile, nil
+}
+
+// Run gets the connection profile of o.Channel, then saves it into o.Dir or prints it to out
+func (o *Options) Run(ctx context.Context, cli dynamic.Interface, out io.Writer, errOut io.Writer) error {
+	channelDetail, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Channel}).Get(ctx, o.Channel, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	network := utils.GetNestedString(channelDetail.Object, "spec", "network")
+	id := utils.GetNestedString(channelDetail.Object, "spec", "id")
+
+	var userOrgs []string
+	if o.Organization == "" {
+		if userOrgs, err = org.ListUserOrganizations(cli, o.Username); err != nil {
+			return err
+		}
+	}
+	organization, err := DiscoverOrganization(o.Organization, userOrgs, relation.Members(channelDetail), o.Channel)
+	if err != nil {
+		return err
+	}
+	profile, err := GetProfile(ctx, cli, o.Channel, organization)
+	if err != nil {
+		return err
+	}
+	orgInfo, ok := profile.Organizations[organization]
+	if !ok {
+		return fmt.Errorf("organization %s not found in the connection profile of channel %s", organization, o.Channel)
+	}
+
+	peers := Peers(profile, organization)
+	if o.ListPeers {
+		return printPeers(out, profile, organization, peers)
+	}
+	if _, ok := orgInfo.Users[o.Username]; !ok {
+		return fmt.Errorf("user %s not found in organization %s of the connection profile of channel %s", o.Username, organization, o.Channel)
+	}
+	// fabric and sdk-go profiles have all peers of the organization
+	var peer string
+	if o.Peer != "" || o.Format == FormatBestchains || o.Format == FormatPeerEnv {
+		if peer, err = DiscoverPeer(o.Peer, peers, organization); err != nil {
+			return err
+		}
+	}
+	if o.Organization == "" {
+		fmt.Fprintf(errOut, "using organization %s\n", organization)
+	}
+	if o.Peer == "" && peer != "" {
+		fmt.Fprintf(errOut, "using peer %s\n", peer)
+	}
+
+	var obj interface{}
+	switch o.Format {
+	case FormatBestchains:
+		obj = map[string]interface{}{
+			"id":       network,
+			"platform": "bestchains",
+			"fabProfile": common.FabProfile{
+				Channel:      id,
+				Organization: organization,
+				User:         orgInfo.Users[o.Username],
+				Enpoint:      profile.Peers[PeerKey(organization, peer)],
+			},
+		}
+	case FormatFabric:
+		obj, err = FilterProfile(profile, profileChannel(profile, id, o.Channel), organization, o.Username)
+	case FormatSDKGo:
+		obj, err = NewSDKConfig(profile, profileChannel(profile, id, o.Channel), organization, o.Username)
+	case FormatPeerEnv:
+		return o.savePeerEnv(out, errOut, profile, id, organization, peer)
+	}
+	if err != nil {
+		return err
+	}
+
+	var objBytes []byte
+	if o.Output == "yaml" {
+		objBytes, err = yaml.Marshal(obj)
+	} else {
+		objBytes, err = json.MarshalIndent(obj, "", "  ")
+		objBytes = append(objBytes, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	if o.Stdout {
+		_, err = out.Write(objBytes)
+		return err
+	}
+
+	name := o.Channel
+	if o.Format != FormatBestchains {
+		name = fmt.Sprintf("%s-%s", o.Channel, o.Format)
+	}
+	targetFile := filepath.Join(o.Dir, fmt.Sprintf("%s.%s", name, o.Output))
+	if err = o.checkOverwrite(targetFile); err != nil {
+		return err
+	}
+	if err = os.WriteFile(targetFile, objBytes, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "connProfile %s saved\n", targetFile)
+	return nil
+}
+
+func (o *Options) savePeerEnv(out io.Writer, errOut io.Writer, profile *common.Profile, id string, organization string, peer string) error {
+	targetDir, err := filepath.Abs(filepath.Join(o.Dir, fmt.Sprintf("%s-%s-%s", o.Channel, organization, peer)))
+	if err != nil {
+		return err
+	}
+	env, err := NewPeerEnv(profile, id, organization, peer, o.Username, targetDir)
+	if err != nil {
+		return err
+	}
+	if err = o.checkOverwrite(targetDir); err != nil {
+		return err
+	}
+	if err = writePeerEnv(env, targetDir); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "peer environment saved in %s, run `source %s` before peer commands\n", targetDir, filepath.Join(targetDir, PeerEnvFile))
+	fmt.Fprintf(errOut, "the CA certificates of organization %s are not in the connection profile, copy them to %s\n", organization, filepath.Join(targetDir, "msp", "cacerts"))
+	return nil
+}
+
+// checkOverwrite returns an error if path exists and o.Force is not set
+func (o *Options) checkOverwrite(path string) error {
+	_, err := os.Stat(path)
+	if err == nil {
+		if o.Force {
+			return nil
+		}
+		return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+	}
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func printPeers(out io.Writer, profile *common.Profile, organization string, peers []string) error {
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "PEER\tURL")
+	for _, peer := range peers {
+		fmt.Fprintf(w, "%s\t%s\n", peer, profile.Peers[PeerKey(organization, peer)].URL)
+	}
+	return w.Flush()
+}
+
 func NewGetConnProfileCmd(option common.Options) *cobra.Command {
-	var (
-		channel        string
-		organization   string
-		peer           string
-		network        string
-		id             string
-		output         string
-		connProfileDir string
-	)
+	o := &Options{}
 
 	cmd := &cobra.Command{
-		Use:   "connProfile",
+		Use:   "connProfile --channel CHANNEL [--org ORG] [--peer PEER]",
 		Short: "Get channel's connection profile",
-		PreRun: func(cmd *cobra.Command, args []string) {
-			connProfileDir = strings.TrimSuffix(connProfileDir, "/")
-			_, err := os.Stat(connProfileDir)
-			if err != nil {
-				if !os.IsNotExist(err) {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				err = os.MkdirAll(connProfileDir, 0755)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-			}
-		},
-		Run: func(cmd *cobra.Command, args []string) {
-			cli, err := common.GetDynamicClient()
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
+		Long: `Get the connection profile of a channel with the identity of the current user.
 
-			channelDetail, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Channel}).Get(context.TODO(), channel, v1.GetOptions{})
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-			network = utils.GetNestedString(channelDetail.Object, "spec", "network")
-			id = utils.GetNestedString(channelDetail.Object, "spec", "id")
-			configmapName := fmt.Sprintf("chan-%s-connection-profile", channel)
-			configmapDetail, err := cli.Resource(schema.GroupVersionResource{Version: common.CoreVersion, Resource: common.Configmap}).Namespace(organization).Get(context.TODO(), configmapName, v1.GetOptions{})
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-			profileJson := utils.GetNestedString(configmapDetail.Object, "binaryData", "profile.json")
-			rawProfileJson, err := base64.StdEncoding.DecodeString(profileJson)
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
+The organization can be omitted if the user belongs to only one member of the channel,
+and the peer can be omitted if the organization has only one peer in the channel.`,
+		Example: `  # save the connection profile of channel ch1 into ~/.bestchains/connProfile/ch1.json
+  bc-cli get connProfile --channel ch1
 
-			var profile *common.Profile
-			err = json.Unmarshal(rawProfileJson, &profile)
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-			username := viper.GetString("auth.username")
-			user := profile.Organizations[organization].Users[username]
-			endpoint := profile.Peers[fmt.Sprintf("%s-%s", organization, peer)]
-			var obj = make(map[string]interface{})
-			obj["id"] = network
-			obj["platform"] = "bestchains"
-			obj["fabProfile"] = common.FabProfile{
-				Channel:      id,
-				Organization: organization,
-				User:         user,
-				Enpoint:      endpoint,
-			}
-			var objBytes []byte
-			var targetFile string
-			if output == "json" {
-				objBytes, _ = json.MarshalIndent(obj, "", "  ")
-				targetFile = fmt.Sprintf("%s/%s.json", connProfileDir, channel)
-			}
-			if output == "yaml" {
-				objBytes, _ = yaml.Marshal(obj)
-				targetFile = fmt.Sprintf("%s/%s.yaml", connProfileDir, channel)
+  # list the peers of org1 in channel ch1
+  bc-cli get connProfile --channel ch1 --org org1 --list-peers
+
+  # print the fabric-sdk-go config of channel ch1
+  bc-cli get connProfile --channel ch1 --format sdk-go --output yaml --stdout`,
+		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			o.Username = viper.GetString("auth.username")
+			if err := o.Validate(); err != nil {
+				return err
 			}
-			f, err := os.Create(targetFile)
-			if err != nil && !os.IsExist(err) {
-				fmt.Fprintln(option.ErrOut, err)
-				return
+			if o.Stdout || o.ListPeers {
+				return nil
 			}
-			_, err = f.Write(objBytes)
+			o.Dir = strings.TrimSuffix(o.Dir, "/")
+			return os.MkdirAll(o.Dir, 0755)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			cli, err := common.GetDynamicClient()
 			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				f.Close()
-				os.Remove(targetFile)
-				return
+				return err
 			}
-			f.Close()
-			fmt.Fprintf(option.Out, "connProfile %s saved\n", targetFile)
+			return o.Run(cmd.Context(), cli, option.Out, option.ErrOut)
 		},
 	}
 
-	cmd.Flags().StringVar(&channel, "channel", "", "channel name")
-	cmd.Flags().StringVar(&organization, "org", "", "organization name")
-	cmd.Flags().StringVar(&peer, "peer", "", "fabric peer name")
-	cmd.Flags().StringVar(&output, "output", "json", "output file type")
-	cmd.Flags().StringVar(&connProfileDir, "dir", common.DefaultConnProfileDir, "output file path")
+	cmd.Flags().StringVar(&o.Channel, "channel", "", "channel name")
+	cmd.Flags().StringVar(&o.Organization, "org", "", "organization name, required if the user belongs to more than one member of the channel")
+	cmd.Flags().StringVar(&o.Peer, "peer", "", "fabric peer name, required if the organization has more than one peer in the channel")
+	cmd.Flags().StringVar(&o.Output, "output", "json", "output file type, json or yaml")
+	cmd.Flags().StringVar(&o.Format, "format", FormatBestchains, fmt.Sprintf("connection profile format, one of %s. "+
+		"fabric is the common connection profile of the organization, sdk-go is the config of fabric-sdk-go, "+
+		"peer-env is a directory with the msp of the user and the environment variables of the peer CLI", strings.Join(Formats, "|")))
+	cmd.Flags().StringVar(&o.Dir, "dir", common.DefaultConnProfileDir, "output file path")
+	cmd.Flags().BoolVar(&o.Stdout, "stdout", false, "print the connection profile instead of saving it")
+	cmd.Flags().BoolVar(&o.Force, "force", false, "overwrite existing files")
+	cmd.Flags().BoolVar(&o.ListPeers, "list-peers", false, "list the peers of the organization in the channel")
+	_ = cmd.MarkFlagRequired("channel")
 	return cmd
 }
+
+// profileChannel returns the key of channel in profile, which is the channel ID in profiles generated by operators.
+// The name of the channel resource is used if the ID is not in profile.
+func profileChannel(profile *common.Profile, id string, channel string) string {
+	if _, ok := profile.Channels[id]; ok || id == "" {
+		return id
+	}
+	return channel
+}
+
+// writePeerEnv writes the files of env and the environment file into dir, keys are only readable by the current user
+func writePeerEnv(env *PeerEnv, dir string) error {
+	for name, content := range env.Files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		perm := os.FileMode(0644)
+		if filepath.Dir(name) == filepath.Dir(peerKey) {
+			perm = 0600
+		}
+		if err := os.WriteFile(path, content, perm); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "msp", "cacerts"), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, PeerEnvFile), []byte(strings.Join(env.Env, "\n")+"\n"), 0644)
+}
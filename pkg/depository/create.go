@@ -17,19 +17,15 @@ limitations under the License.
 package depository
 
 import (
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
-	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bestchains/bc-cli/pkg/account"
+	"github.com/bestchains/bc-cli/pkg/client/depository"
 	"github.com/bestchains/bc-cli/pkg/common"
-	"github.com/bestchains/bc-cli/pkg/nonce"
-	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -74,23 +70,16 @@ func NewCreateDepositoryCmd() *cobra.Command {
 				return fmt.Errorf("no host provided")
 			}
 
-			valueBase64 := generateValueDepotBase64(n, t, id, p)
+			client, err := depository.NewClient(host)
+			if err != nil {
+				return err
+			}
+			value := newValueDepository(n, t, id, p)
 
+			var resp *depository.PutValueResponse
 			if accountAddress == "" {
 				fmt.Println("creating untrusted depository without account endorsement")
-				postValue := url.Values{}
-				postValue.Add("value", valueBase64)
-
-				// -> http://localhost/basic/putUntrustValue
-				host = fmt.Sprintf("%s%s", host, common.CreateUntrustedDepository)
-				resp, err := uhttp.Do(host, http.MethodPost, map[string]string{
-					"Content-Type": "application/x-www-form-urlencoded",
-				}, []byte(postValue.Encode()))
-				if err != nil {
-					return err
-				}
-				fmt.Print(string(resp))
-				return nil
+				resp, err = client.PutUntrustValue(cmd.Context(), value)
 			} else {
 				fmt.Printf("creating trusted depository with account %s endorsement \n", accountAddress)
 				//read account info
@@ -102,32 +91,18 @@ func NewCreateDepositoryCmd() *cobra.Command {
 				if err != nil {
 					return err
 				}
-				// get nonce
-				currNonce, err := nonce.Get(host, common.DepositoryCurrentNonce, acc.Address)
-				if err != nil {
-					return err
-				}
-				// generate message
-				msgBase64, err := acc.GenerateAndSignMessage(currNonce, valueBase64)
-				if err != nil {
-					return err
-				}
-				// POST PutValue request
-				postValue := url.Values{}
-				postValue.Add("message", msgBase64)
-				postValue.Add("value", valueBase64)
-
-				// -> http://localhost/basic/putValue
-				host = fmt.Sprintf("%s%s", host, common.CreateDepository)
-				resp, err := uhttp.Do(host, http.MethodPost, map[string]string{
-					"Content-Type": "application/x-www-form-urlencoded",
-				}, []byte(postValue.Encode()))
-				if err != nil {
-					return err
-				}
-				fmt.Print(string(resp))
+				resp, err = client.PutValue(cmd.Context(), acc, value)
+			}
+			if err != nil {
+				return err
+			}
+			if resp.KID == "" {
+				// print the response as is if it is not in the known shape
+				fmt.Println(strings.TrimSpace(string(resp.Raw)))
 				return nil
 			}
+			fmt.Printf("depository/%s created\n", resp.KID)
+			return nil
 		},
 	}
 	// Set up command line flags for depository
@@ -153,35 +128,15 @@ func NewCreateDepositoryCmd() *cobra.Command {
 	return cmd
 }
 
-// generateValueDepotBase64 generates a Base64-encoded string representation of a ValueDepository object.
+// newValueDepository generates a ValueDepository object trusted at now.
 // The ValueDepository object contains the name, content type, content ID, and trusted timestamp of a value.
 // It also includes the platform on which the value was created.
-//
-// Parameters:
-// name (string): The name of the value.
-// contentType (string): The content type of the value.
-// contentID (string): The content ID of the value.
-// platform (string): The platform on which the value was created.
-//
-// Returns:
-// string: A Base64-encoded string representation of the ValueDepository object.
-func generateValueDepotBase64(name string, contentType string, contentID string, platform string) string {
-	// Generate ValueDepository
-	valDep := ValueDepository{
+func newValueDepository(name string, contentType string, contentID string, platform string) ValueDepository {
+	return ValueDepository{
 		Name:             name,
 		ContentType:      contentType,
 		ContentID:        contentID,
 		TrustedTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
 		Platform:         platform,
 	}
-
-	// Marshal & encoding
-	rawVal, err := json.Marshal(valDep)
-	if err != nil {
-		return ""
-	}
-
-	value := base64.StdEncoding.EncodeToString(rawVal)
-
-	return value
 }
@@ -17,9 +17,18 @@ limitations under the License.
 package depository
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/bestchains/bc-cli/pkg/account"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/testserver"
 )
 
 func TestValueDepotGen(t *testing.T) {
@@ -31,7 +40,10 @@ func TestValueDepotGen(t *testing.T) {
 		Platform:         "test platform",
 	}
 
-	genBase := generateValueDepotBase64("test name", "test type", "test ID", "test platform")
+	genBase, err := newValueDepository("test name", "test type", "test ID", "test platform").Encode()
+	if err != nil {
+		t.Fatalf("encode valDep failed: " + err.Error())
+	}
 
 	decodeRes, err := base64.StdEncoding.DecodeString(genBase)
 	if err != nil {
@@ -48,3 +60,31 @@ func TestValueDepotGen(t *testing.T) {
 		t.Fatalf("generated valDep don't match. expect name '%s', got '%s'", expectValDepot.Name, resValDepot.Name)
 	}
 }
+
+func TestCreateAndGetDepository(t *testing.T) {
+	server := httptest.NewServer(testserver.New())
+	defer server.Close()
+
+	walletDir := t.TempDir()
+	wallet, err := account.NewLocalWallet(walletDir)
+	assert.NoError(t, err)
+	acc, err := account.NewAccount()
+	assert.NoError(t, err)
+	assert.NoError(t, wallet.StoreAccount(acc))
+
+	for _, args := range [][]string{
+		{"--name", "trusted", "--contentID", "hash"},
+		{"--name", "trusted", "--contentID", "hash", "--account", acc.Address, "--wallet", walletDir},
+	} {
+		cmd := NewCreateDepositoryCmd()
+		cmd.SetArgs(append(args, "--host", server.URL))
+		assert.NoError(t, cmd.Execute())
+	}
+
+	output := new(bytes.Buffer)
+	cmd := NewGetDepositoryCmd(common.Options{IOStreams: genericclioptions.IOStreams{Out: output, ErrOut: output}})
+	cmd.SetArgs([]string{"--host", server.URL, "--name", "trusted"})
+	assert.NoError(t, cmd.Execute())
+	assert.Contains(t, output.String(), acc.Address)
+	assert.Contains(t, output.String(), "untrusted")
+}
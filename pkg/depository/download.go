@@ -17,10 +17,9 @@ limitations under the License.
 package depository
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"sync"
 	"time"
@@ -28,13 +27,12 @@ import (
 	"github.com/vbauerster/mpb/v8"
 	"github.com/vbauerster/mpb/v8/decor"
 
-	"github.com/bestchains/bc-cli/pkg/auth"
+	"github.com/bestchains/bc-cli/pkg/client/depository"
 	"github.com/bestchains/bc-cli/pkg/common"
 	"github.com/bestchains/bc-cli/pkg/utils"
 )
 
-func download(host, style string, kids []string, option common.Options) {
-
+func download(ctx context.Context, client *depository.Client, style string, kids []string, option common.Options) {
 	limit := make(chan struct{}, 3)
 	var wg sync.WaitGroup
 	p := mpb.New(mpb.WithWaitGroup(&wg))
@@ -49,31 +47,14 @@ func download(host, style string, kids []string, option common.Options) {
 			}()
 
 			name := kid + ".pdf"
-			u := fmt.Sprintf("%s%s?style=%s", host, fmt.Sprintf(common.DepositoryCertificate, kid), style)
-			req, err := http.NewRequest(http.MethodGet, u, nil)
-			if err != nil {
-				fmt.Fprintf(option.ErrOut, "new request for %s error %s", name, err)
-				return
-			}
-			auth.AddAuthHeader(req)
-			client := &http.Client{
-				// the size mf the certificate file is usually around 3-10M, so the 10s timeout time is more reasonable.
-				Timeout: 10 * time.Second,
-				Transport: &http.Transport{
-					TLSClientConfig: &tls.Config{
-						InsecureSkipVerify: true,
-					},
-				},
-			}
-			resp, err := client.Do(req)
+			body, contentLength, err := client.Certificate(ctx, kid, style)
 			if err != nil {
 				fmt.Fprintf(option.ErrOut, "do request for %s error %s", name, err)
 				return
 			}
-			defer resp.Body.Close()
+			defer body.Close()
 
 			buf := make([]byte, 512)
-			contentLength := resp.ContentLength
 			bar := p.AddBar(contentLength,
 				mpb.PrependDecorators(decor.Name(fmt.Sprintf("Downloading %s", name))),
 				mpb.BarWidth(50),
@@ -86,7 +67,7 @@ func download(host, style string, kids []string, option common.Options) {
 			}
 			defer f.Close()
 			for {
-				n, err := resp.Body.Read(buf)
+				n, err := body.Read(buf)
 				if err != nil {
 					if err != io.EOF {
 						fmt.Fprintf(option.ErrOut, "read %s's body error %s", name, err)
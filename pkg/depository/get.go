@@ -17,43 +17,25 @@ limitations under the License.
 package depository
 
 import (
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/bestchains/bc-cli/pkg/client/depository"
 	"github.com/bestchains/bc-cli/pkg/common"
 	"github.com/bestchains/bc-cli/pkg/printer"
-	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
 )
 
-func ConstructQuery(cmd *cobra.Command) string {
-	query := url.Values{}
-	from, _ := cmd.Flags().GetInt("from")
-	if from != 0 {
-		query.Add("from", fmt.Sprintf("%d", from))
-	}
-	size, _ := cmd.Flags().GetInt("size")
-	if size != 0 {
-		query.Add("size", fmt.Sprintf("%d", size))
-	}
-	kid, _ := cmd.Flags().GetString("kid")
-	if kid != "" {
-		query.Add("kid", kid)
-	}
-	name, _ := cmd.Flags().GetString("name")
-	if name != "" {
-		query.Add("name", name)
-	}
-	contentName, _ := cmd.Flags().GetString("contentName")
-	if contentName != "" {
-		query.Add("contentName", contentName)
-	}
-
-	return fmt.Sprintf("%s?%s", common.ListDepository, query.Encode())
+// listOptions returns the list options of the sdk from flags of cmd
+func listOptions(cmd *cobra.Command) depository.ListOptions {
+	options := depository.ListOptions{}
+	options.From, _ = cmd.Flags().GetInt("from")
+	options.Size, _ = cmd.Flags().GetInt("size")
+	options.KID, _ = cmd.Flags().GetString("kid")
+	options.Name, _ = cmd.Flags().GetString("name")
+	options.ContentName, _ = cmd.Flags().GetString("contentName")
+	return options
 }
 
 var headers = []string{"index", "kid", "platform", "operator", "owner", "blockNumber", "time"}
@@ -67,33 +49,28 @@ func NewGetDepositoryCmd(option common.Options) *cobra.Command {
 			if host == "" {
 				return fmt.Errorf("no host provided")
 			}
+			client, err := depository.NewClient(host)
+			if err != nil {
+				return err
+			}
 			certificate, _ := cmd.Flags().GetBool("certificate")
 			if certificate {
 				if len(args) == 0 {
 					return fmt.Errorf("no kid provided")
 				}
 				style, _ := cmd.Flags().GetString("certificateStyle")
-				download(host, style, args, option)
+				download(cmd.Context(), client, style, args, option)
 				return nil
 			}
 			if len(args) == 0 {
-				u := fmt.Sprintf("%s%s", host, ConstructQuery(cmd))
-				x, err := uhttp.Do(u, http.MethodGet, nil, nil)
+				list, err := client.List(cmd.Context(), listOptions(cmd))
 				if err != nil {
 					fmt.Fprintf(option.ErrOut, "Error failed to get depository: %s\n", err.Error())
 					return err
 				}
-				var data struct {
-					Data  []Depository `json:"data"`
-					Count int64        `json:"count"`
-				}
-				if err := json.Unmarshal(x, &data); err != nil {
-					fmt.Fprintf(option.ErrOut, "unmarhsal response error %s\n", err.Error())
-					return err
-				}
-				xx := make([]printer.Printer, len(data.Data))
-				for i := 0; i < len(data.Data); i++ {
-					xx[i] = data.Data[i]
+				xx := make([]printer.Printer, len(list.Data))
+				for i := 0; i < len(list.Data); i++ {
+					xx[i] = Depository(list.Data[i])
 				}
 				printer.Print(option.Out, headers, xx)
 				return nil
@@ -101,18 +78,12 @@ func NewGetDepositoryCmd(option common.Options) *cobra.Command {
 			errMsg := make([]string, 0)
 			pobj := make([]printer.Printer, 0)
 			for _, kid := range args {
-				u := fmt.Sprintf("%s%s", host, fmt.Sprintf(common.GetDepository, kid))
-				x, err := uhttp.Do(u, http.MethodGet, nil, nil)
+				o, err := client.Get(cmd.Context(), kid)
 				if err != nil {
 					errMsg = append(errMsg, err.Error())
 					continue
 				}
-				var o Depository
-				if err := json.Unmarshal(x, &o); err != nil {
-					errMsg = append(errMsg, err.Error())
-					continue
-				}
-				pobj = append(pobj, o)
+				pobj = append(pobj, Depository(*o))
 			}
 			printer.Print(option.Out, headers, pobj)
 			for _, e := range errMsg {
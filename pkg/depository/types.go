@@ -19,23 +19,12 @@ package depository
 import (
 	"fmt"
 	"time"
-)
 
-type Depository struct {
-	Index       string `json:"index" pg:"index"`
-	KID         string `json:"kid" pg:"kid,pk"`
-	Platform    string `json:"platform" pg:"platform"`
-	Operator    string `json:"operator" pg:"operator"`
-	Owner       string `json:"owner" pg:"owner"`
-	BlockNumber uint64 `json:"blockNumber" pg:"blockNumber"`
+	"github.com/bestchains/bc-cli/pkg/client/depository"
+)
 
-	// Content related
-	Name             string `json:"name" pg:"name"`
-	ContentName      string `json:"contentName" pg:"contentName"`
-	ContentID        string `json:"contentID" pg:"contentID"`
-	ContentType      string `json:"contentType" pg:"contentType"`
-	TrustedTimestamp int64  `json:"trustedTimestamp" pg:"trustedTimestamp"`
-}
+// Depository wraps the depository of the sdk to print it as a table row
+type Depository depository.Depository
 
 func (d Depository) GetByHeader(s string) string {
 	switch s {
@@ -67,10 +56,5 @@ func (d Depository) GetByHeader(s string) string {
 	return "<none>"
 }
 
-type ValueDepository struct {
-	Name             string `json:"name"`
-	ContentType      string `json:"contentType"`
-	ContentID        string `json:"contentID"` // hash of the file
-	TrustedTimestamp string `json:"trustedTimestamp"`
-	Platform         string `json:"platform"`
-}
+// ValueDepository is the value to put into the depository
+type ValueDepository = depository.ValueDepository
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorsepolicy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+var (
+	endorsePolicyGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.EndorsePolicy}
+	channelGVR       = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Channel}
+	chaincodeGVR     = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Chaincode}
+)
+
+// Validate returns an error if any MSP ID in policy is not a member of the channel with members
+func Validate(policy *Policy, channelName string, members []string) error {
+	var unknown []string
+	for _, id := range policy.MSPIDs() {
+		if !utils.ContainsString(members, id) {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("policy references %s which are not members of channel %s (members: %s)",
+			strings.Join(unknown, ", "), channelName, strings.Join(members, ", "))
+	}
+	return nil
+}
+
+// NewEndorsePolicy builds the EndorsePolicy CR of channel
+func NewEndorsePolicy(name string, channel string, policy *Policy, description string) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"channel": channel,
+		"value":   policy.String(),
+	}
+	if description != "" {
+		spec["description"] = description
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "EndorsePolicy",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": spec,
+	}}
+}
+
+func NewCreateEndorsePolicyCmd(option common.Options) *cobra.Command {
+	var (
+		channelName string
+		value       string
+		description string
+		dryRun      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "ep NAME --channel CHANNEL --policy POLICY [--description DESC] [--dry-run]",
+		Short: "Create an endorsepolicy of a channel",
+		Long: `Create an endorsepolicy of a channel
+
+The policy is written in the fabric signature policy DSL, it is parsed and printed as a tree for review,
+and all MSP IDs in it must be members of the channel.
+
+Examples:
+  # Require Org1 and any of Org2 and Org3 to endorse
+  bc-cli create ep ep1 --channel=<channel-name> --policy="AND('Org1.member', OR('Org2.member', 'Org3.member'))"
+
+  # Require any two of the three organizations to endorse, without creating the endorsepolicy
+  bc-cli create ep ep2 --channel=<channel-name> --policy="OutOf(2, 'Org1.peer', 'Org2.peer', 'Org3.peer')" --dry-run
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policy, err := Parse(value)
+			if err != nil {
+				return err
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			ch, err := cli.Resource(channelGVR).Get(cmd.Context(), channelName, v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if err := Validate(policy, channelName, relation.Members(ch)); err != nil {
+				return err
+			}
+			policy.PrintTree(option.Out)
+
+			ep := NewEndorsePolicy(args[0], channelName, policy, description)
+			if dryRun {
+				return (&printers.YAMLPrinter{}).PrintObj(ep, option.Out)
+			}
+			if _, err := cli.Resource(endorsePolicyGVR).Create(cmd.Context(), ep, v1.CreateOptions{}); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fmt.Fprintf(option.Out, "endorsepolicy/%s created\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&channelName, "channel", "", "channel of the endorsepolicy")
+	cmd.Flags().StringVar(&value, "policy", "", "signature policy, such as AND('Org1.member', OR('Org2.member', 'Org3.member'))")
+	cmd.Flags().StringVar(&description, "description", "", "description of the endorsepolicy")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only print the policy tree and the manifest which would be submitted")
+	_ = cmd.MarkFlagRequired("channel")
+	_ = cmd.MarkFlagRequired("policy")
+	return cmd
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorsepolicy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// CheckDeletable returns an error if the endorsepolicy is still referenced by any chaincode
+func CheckDeletable(ctx context.Context, cli dynamic.Interface, name string) error {
+	if _, err := cli.Resource(endorsePolicyGVR).Get(ctx, name, v1.GetOptions{}); err != nil {
+		return err
+	}
+	chaincodes, err := cli.Resource(chaincodeGVR).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var users []string
+	for _, cc := range chaincodes.Items {
+		if utils.GetNestedString(cc.Object, "spec", "endorsePolicyRef", "name") == name {
+			users = append(users, cc.GetName())
+		}
+	}
+	if len(users) > 0 {
+		sort.Strings(users)
+		return fmt.Errorf("endorsepolicy %s is still used by chaincode %s", name, strings.Join(users, ","))
+	}
+	return nil
+}
+
+func NewDeleteEndorsePolicyCmd(option common.Options) *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "ep NAME...",
+		Short: "Delete endorsepolicies which are not used by any chaincode",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			var lastErr error
+			for _, name := range utils.RemoveDuplicateForStringSlice(args) {
+				if err := CheckDeletable(cmd.Context(), cli, name); err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					lastErr = err
+					continue
+				}
+				if !yes && !utils.Confirm(option.In, option.Out, fmt.Sprintf("Delete endorsepolicy %s?", name)) {
+					fmt.Fprintf(option.Out, "endorsepolicy/%s skipped\n", name)
+					continue
+				}
+				if err := cli.Resource(endorsePolicyGVR).Delete(cmd.Context(), name, v1.DeleteOptions{}); err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					lastErr = err
+					continue
+				}
+				fmt.Fprintf(option.Out, "endorsepolicy/%s deleted\n", name)
+			}
+			return lastErr
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "delete without confirmation")
+	return cmd
+}
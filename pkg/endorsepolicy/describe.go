@@ -0,0 +1,53 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorsepolicy
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+func describeDetails(w describe.PrefixWriter, ep *unstructured.Unstructured) {
+	value := utils.GetNestedString(ep.Object, "spec", "value")
+	w.Write(describe.LEVEL_0, "Name:\t%s\n", ep.GetName())
+	w.Write(describe.LEVEL_0, "Channel:\t%s\n", utils.GetNestedString(ep.Object, "spec", "channel"))
+	w.Write(describe.LEVEL_0, "Policy:\t%s\n", value)
+	if policy, err := Parse(value); err == nil {
+		tree := &strings.Builder{}
+		policy.PrintTree(tree)
+		w.Write(describe.LEVEL_0, "Policy Tree:\n")
+		for _, line := range strings.Split(strings.TrimSuffix(tree.String(), "\n"), "\n") {
+			w.Write(describe.LEVEL_1, "%s\n", line)
+		}
+	}
+	relation.WriteCommon(w, ep)
+}
+
+func NewDescribeEndorsePolicyCmd(option common.Options) *cobra.Command {
+	return relation.NewDescribeCmd(option, relation.KindEndorsePolicy, &cobra.Command{
+		Use:     "ep NAME...",
+		Aliases: []string{"endorsepolicy"},
+		Short:   "Show details of endorsepolicies with their channel, the chaincodes using them and events",
+	}, describeDetails)
+}
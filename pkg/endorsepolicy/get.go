@@ -18,24 +18,35 @@ package endorsepolicy
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/kubectl/pkg/cmd/get"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/resource"
+	"github.com/bestchains/bc-cli/pkg/utils"
 )
 
+// Kind is the kind of endorsepolicies
+var Kind = resource.Kind{
+	Kind:    relation.KindEndorsePolicy,
+	Name:    "endorsepolicy",
+	Aliases: []string{"ep"},
+	GVR:     endorsePolicyGVR,
+	Columns: []resource.Column{
+		{Name: "Channel", Value: resource.Field("spec", "channel")},
+		{Name: "Value", Wide: true, Value: resource.Field("spec", "value")},
+	},
+}
+
 func NewGetEndorsePolicyCmd(option common.Options) *cobra.Command {
-	defaultPrinter := get.NewGetPrintFlags()
+	flags := resource.NewGetFlags()
 
 	var (
 		network string
@@ -57,121 +68,73 @@ Examples:
   bc-cli get ep --network=<network-name> ep1 ep2
 `,
 
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
 			client, err := common.GetDynamicClient()
 			if err != nil {
 				fmt.Fprintln(option.ErrOut, err)
-				return
+				return err
 			}
-			ibpNetwork, err := client.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Network}).
-				Get(context.TODO(), network, v1.GetOptions{})
+			ibpNetwork, err := client.Resource(relation.GVR(relation.KindNetwork)).Get(cmd.Context(), network, v1.GetOptions{})
 			if err != nil {
 				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-
-			channels, _, _ := unstructured.NestedStringSlice(ibpNetwork.Object, "status", "channels")
-			if len(channels) == 0 {
-				fmt.Fprintf(option.Out, "network %s don't have any channel", network)
-				return
+				return err
 			}
 
+			var errs []error
 			chanMap := make(map[string]struct{})
-			for _, ch := range channels {
-				chanMap[ch] = struct{}{}
-			}
-
-			errOutput := make([]error, 0)
+			channels, _, _ := unstructured.NestedStringSlice(ibpNetwork.Object, "status", "channels")
 			if len(channel) > 0 {
-				chans := strings.Split(channel, ",")
-				filterChan := make(map[string]struct{})
-				for _, ch := range chans {
-					if _, ok := chanMap[ch]; !ok {
-						errOutput = append(errOutput, fmt.Errorf("channel %s don't belong to network %s", ch, network))
+				for _, ch := range strings.Split(channel, ",") {
+					if !utils.ContainsString(channels, ch) {
+						err := fmt.Errorf("channel %s don't belong to network %s", ch, network)
+						fmt.Fprintln(option.ErrOut, err)
+						errs = append(errs, err)
 						continue
 					}
-					filterChan[ch] = struct{}{}
-				}
-				if len(filterChan) == 0 {
-					fmt.Fprintln(option.Out, "after filtering, there are no compliant channels.")
-					return
+					chanMap[ch] = struct{}{}
 				}
-				chanMap = filterChan
-			}
-
-			epMap := make(map[string]struct{})
-			if len(args) > 0 {
-				for _, epName := range args {
-					epMap[epName] = struct{}{}
+				if len(chanMap) == 0 {
+					return utilerrors.NewAggregate(errs)
 				}
-			}
-
-			endorsePolicyList, err := client.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.EndorsePolicy}).
-				List(cmd.Context(), v1.ListOptions{})
-			if err != nil && !errors.IsNotFound(err) {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-			if len(endorsePolicyList.Items) == 0 {
-				fmt.Fprintln(option.Out, "no endorsepolicy found")
-				return
-			}
-
-			outputList := corev1.List{
-				TypeMeta: v1.TypeMeta{
-					Kind:       "List",
-					APIVersion: "v1",
-				},
-				ListMeta: v1.ListMeta{},
-			}
-
-			for i, item := range endorsePolicyList.Items {
-				epName := item.GetName()
-				epChannel, _, _ := unstructured.NestedString(item.Object, "spec", "channel")
-				if _, inChannel := chanMap[epChannel]; !inChannel {
-					continue
-				}
-				if len(epMap) > 0 {
-					if _, ok := epMap[epName]; !ok {
-						continue
-					}
-				}
-				outputList.Items = append(outputList.Items, runtime.RawExtension{Object: &endorsePolicyList.Items[i]})
-			}
-
-			var obj runtime.Object
-
-			if len(outputList.Items) != 1 {
-				listData, err := json.Marshal(outputList)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, listData)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				obj = converted
 			} else {
-				obj = outputList.Items[0].Object
+				for _, ch := range channels {
+					chanMap[ch] = struct{}{}
+				}
 			}
 
-			p, err := defaultPrinter.ToPrinter()
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-			_ = p.PrintObj(obj, option.Out)
-			for _, e := range errOutput {
-				fmt.Fprintln(option.ErrOut, e)
+			// channels created in the network later are also kept unless channels are specified
+			options := resource.Options{Names: args, Filter: InChannels(cmd.Context(), client, network, chanMap, len(channel) == 0)}
+			if err := flags.Run(cmd.Context(), client, option, Kind, options); err != nil {
+				errs = append(errs, err)
 			}
+			return utilerrors.NewAggregate(errs)
 		},
 	}
 
-	defaultPrinter.AddFlags(cmd)
+	flags.AddFlags(cmd)
 	cmd.Flags().StringVar(&network, "network", "", "choose a blockchain network")
 	cmd.Flags().StringVar(&channel, "channel", "", "support multiple channel filtering, separated by commas")
 	_ = cmd.MarkFlagRequired("network")
 	return cmd
 }
+
+// InChannels returns a filter keeping endorsepolicies of channels. If others is true, endorsepolicies of
+// the other channels in network are also kept, such channels are fetched once and added to channels.
+func InChannels(ctx context.Context, cli dynamic.Interface, network string, channels map[string]struct{}, others bool) func(*unstructured.Unstructured) bool {
+	return func(ep *unstructured.Unstructured) bool {
+		epChannel := utils.GetNestedString(ep.Object, "spec", "channel")
+		if _, ok := channels[epChannel]; ok {
+			return true
+		}
+		if !others {
+			return false
+		}
+		ch, err := cli.Resource(channelGVR).Get(ctx, epChannel, v1.GetOptions{})
+		if err != nil || utils.GetNestedString(ch.Object, "spec", "network") != network {
+			return false
+		}
+		channels[epChannel] = struct{}{}
+		return true
+	}
+}
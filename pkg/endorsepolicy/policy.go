@@ -0,0 +1,309 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorsepolicy
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Gates of signature policies
+const (
+	GateAnd   = "AND"
+	GateOr    = "OR"
+	GateOutOf = "OutOf"
+)
+
+// roles which can be used in principals, such as Org1.member
+var roles = map[string]bool{
+	"member":  true,
+	"admin":   true,
+	"client":  true,
+	"peer":    true,
+	"orderer": true,
+}
+
+// Policy is a fabric signature policy, which is either a principal like 'Org1.member'
+// or a gate like AND(...), OR(...) and OutOf(n, ...) with rules
+type Policy struct {
+	// Gate is empty for principals
+	Gate string
+	// N is the number of rules to satisfy, which is len(Rules) for AND and 1 for OR
+	N     int
+	Rules []*Policy
+
+	MSPID string
+	Role  string
+}
+
+// String returns the policy in the signature policy DSL
+func (p *Policy) String() string {
+	if p.Gate == "" {
+		return fmt.Sprintf("'%s.%s'", p.MSPID, p.Role)
+	}
+	rules := make([]string, 0, len(p.Rules))
+	for _, r := range p.Rules {
+		rules = append(rules, r.String())
+	}
+	if p.Gate == GateOutOf {
+		return fmt.Sprintf("OutOf(%d, %s)", p.N, strings.Join(rules, ", "))
+	}
+	return fmt.Sprintf("%s(%s)", p.Gate, strings.Join(rules, ", "))
+}
+
+// MSPIDs returns the sorted MSP IDs in the principals of the policy
+func (p *Policy) MSPIDs() []string {
+	set := map[string]bool{}
+	var walk func(*Policy)
+	walk = func(p *Policy) {
+		if p.Gate == "" {
+			set[p.MSPID] = true
+		}
+		for _, r := range p.Rules {
+			walk(r)
+		}
+	}
+	walk(p)
+	ids := make([]string, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// MinOrganizations returns the least number of distinct organizations which must sign to satisfy the policy.
+// An organization signs with as many identities as needed, so it satisfies all principals of its MSP ID.
+func (p *Policy) MinOrganizations() int {
+	ids := p.MSPIDs()
+	signers := make(map[string]bool, len(ids))
+	// choose tries the sets of size n from ids[start:] together with the signers chosen so far
+	var choose func(start int, n int) bool
+	choose = func(start int, n int) bool {
+		if n == 0 {
+			return p.satisfiedBy(signers)
+		}
+		for i := start; i <= len(ids)-n; i++ {
+			signers[ids[i]] = true
+			ok := choose(i+1, n-1)
+			delete(signers, ids[i])
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+	for n := 1; n <= len(ids); n++ {
+		if choose(0, n) {
+			return n
+		}
+	}
+	return len(ids)
+}
+
+// satisfiedBy reports whether the policy is satisfied when the organizations in signers sign
+func (p *Policy) satisfiedBy(signers map[string]bool) bool {
+	if p.Gate == "" {
+		return signers[p.MSPID]
+	}
+	satisfied := 0
+	for _, r := range p.Rules {
+		if r.satisfiedBy(signers) {
+			satisfied++
+		}
+	}
+	return satisfied >= p.N
+}
+
+// PrintTree prints the policy as a tree, such as
+//
+//	AND
+//	├── 'Org1.member'
+//	└── OR
+//	    ├── 'Org2.member'
+//	    └── 'Org3.member'
+func (p *Policy) PrintTree(out io.Writer) {
+	p.printTree(out, "", "")
+}
+
+func (p *Policy) printTree(out io.Writer, prefix string, childPrefix string) {
+	switch p.Gate {
+	case "":
+		fmt.Fprintf(out, "%s'%s.%s'\n", prefix, p.MSPID, p.Role)
+		return
+	case GateOutOf:
+		fmt.Fprintf(out, "%s%s %d of %d\n", prefix, p.Gate, p.N, len(p.Rules))
+	default:
+		fmt.Fprintf(out, "%s%s\n", prefix, p.Gate)
+	}
+	for i, r := range p.Rules {
+		if i == len(p.Rules)-1 {
+			r.printTree(out, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			r.printTree(out, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// Parse parses a signature policy like AND('Org1.member', OR('Org2.member', 'Org3.member')).
+// Gates are case insensitive and principals are quoted by single or double quotes.
+func Parse(policy string) (*Policy, error) {
+	p := &parser{input: policy}
+	result, err := p.parsePolicy()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q after the policy", p.input[p.pos:])
+	}
+	return result, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid policy at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && strings.ContainsRune(" \t\r\n", rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// expect consumes c after spaces
+func (p *parser) expect(c byte) error {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return p.errorf("expected %q but got the end", c)
+	}
+	if p.input[p.pos] != c {
+		return p.errorf("expected %q but got %q", c, p.input[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+// word returns the next identifier or number
+func (p *parser) word() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parser) parsePolicy() (*Policy, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("expected a principal or a gate but got the end")
+	}
+	if c := p.input[p.pos]; c == '\'' || c == '"' {
+		return p.parsePrincipal()
+	}
+	start := p.pos
+	word := p.word()
+	var gate string
+	switch strings.ToLower(word) {
+	case "and":
+		gate = GateAnd
+	case "or":
+		gate = GateOr
+	case "outof":
+		gate = GateOutOf
+	default:
+		p.pos = start
+		return nil, p.errorf("unknown gate %q, must be AND, OR or OutOf", word)
+	}
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	result := &Policy{Gate: gate}
+	if gate == GateOutOf {
+		start := p.pos
+		n, err := strconv.Atoi(p.word())
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("OutOf requires the number of rules to satisfy first")
+		}
+		result.N = n
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		rule, err := p.parsePolicy()
+		if err != nil {
+			return nil, err
+		}
+		result.Rules = append(result.Rules, rule)
+		p.skipSpaces()
+		if p.pos < len(p.input) && p.input[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		break
+	}
+	switch gate {
+	case GateAnd:
+		result.N = len(result.Rules)
+	case GateOr:
+		result.N = 1
+	case GateOutOf:
+		if result.N < 1 || result.N > len(result.Rules) {
+			return nil, fmt.Errorf("invalid policy: OutOf(%d, ...) must require 1 to %d rules", result.N, len(result.Rules))
+		}
+	}
+	return result, nil
+}
+
+func (p *parser) parsePrincipal() (*Policy, error) {
+	quote := p.input[p.pos]
+	start := p.pos
+	end := strings.IndexByte(p.input[p.pos+1:], quote)
+	if end < 0 {
+		return nil, p.errorf("unterminated principal")
+	}
+	principal := p.input[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	i := strings.LastIndexByte(principal, '.')
+	if i <= 0 || i == len(principal)-1 {
+		p.pos = start
+		return nil, p.errorf("invalid principal %q, must be MSPID.ROLE", principal)
+	}
+	mspID, role := principal[:i], principal[i+1:]
+	if !roles[role] {
+		p.pos = start
+		return nil, p.errorf("unknown role %q of %s, must be one of admin, client, member, orderer and peer", role, mspID)
+	}
+	return &Policy{MSPID: mspID, Role: role}, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endorsepolicy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestParse(t *testing.T) {
+	policy, err := Parse(`and('Org1.member',  OR("Org2.member", 'Org3.peer') )`)
+	assert.NoError(t, err)
+	assert.Equal(t, "AND('Org1.member', OR('Org2.member', 'Org3.peer'))", policy.String())
+	assert.Equal(t, []string{"Org1", "Org2", "Org3"}, policy.MSPIDs())
+	assert.Equal(t, 2, policy.N)
+	assert.Equal(t, 2, policy.MinOrganizations())
+
+	out := &bytes.Buffer{}
+	policy.PrintTree(out)
+	assert.Equal(t, `AND
+├── 'Org1.member'
+└── OR
+    ├── 'Org2.member'
+    └── 'Org3.peer'
+`, out.String())
+
+	policy, err = Parse("OutOf(2, 'org1.admin', 'org2.admin', OR('org3.member', 'org1.member'))")
+	assert.NoError(t, err)
+	assert.Equal(t, "OutOf(2, 'org1.admin', 'org2.admin', OR('org3.member', 'org1.member'))", policy.String())
+	// org1 alone signs for both org1.admin and org1.member
+	assert.Equal(t, 1, policy.MinOrganizations())
+	out.Reset()
+	policy.PrintTree(out)
+	assert.Equal(t, `OutOf 2 of 3
+├── 'org1.admin'
+├── 'org2.admin'
+└── OR
+    ├── 'org3.member'
+    └── 'org1.member'
+`, out.String())
+
+	policy, err = Parse("OutOf(2, 'org1.member', 'org1.peer', 'org2.member')")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, policy.MinOrganizations())
+	policy, err = Parse("AND(OR('org1.member', 'org2.member'), OR('org2.member', 'org3.member'), 'org4.member')")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, policy.MinOrganizations())
+
+	// MSP IDs may contain dots
+	policy, err = Parse("'org1.example.com.member'")
+	assert.NoError(t, err)
+	assert.Equal(t, "org1.example.com", policy.MSPID)
+
+	for _, invalid := range []string{
+		"",
+		"AND()",
+		"AND('Org1.member'",
+		"AND('Org1.member',)",
+		"NOT('Org1.member')",
+		"AND('Org1.member') 'Org2.member'",
+		"'Org1.owner'",
+		"'Org1'",
+		"'Org1.member",
+		"OutOf('Org1.member')",
+		"OutOf(3, 'Org1.member', 'Org2.member')",
+		"OutOf(0, 'Org1.member')",
+	} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+	_, err = Parse("AND('Org1.member', XOR('Org2.member'))")
+	assert.EqualError(t, err, `invalid policy at position 20: unknown gate "XOR", must be AND, OR or OutOf`)
+}
+
+func TestValidate(t *testing.T) {
+	policy, err := Parse("AND('org1.member', OR('org2.member', 'org4.member', 'org5.member'))")
+	assert.NoError(t, err)
+	assert.EqualError(t, Validate(policy, "ch1", []string{"org1", "org2", "org3"}),
+		"policy references org4, org5 which are not members of channel ch1 (members: org1, org2, org3)")
+	assert.NoError(t, Validate(policy, "ch1", []string{"org1", "org2", "org4", "org5"}))
+}
+
+func TestCheckDeletable(t *testing.T) {
+	policy, _ := Parse("'org1.member'")
+	cc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "ibp.com/v1beta1",
+		"kind":       "Chaincode",
+		"metadata":   map[string]interface{}{"name": "ch1-basic"},
+		"spec": map[string]interface{}{
+			"endorsePolicyRef": map[string]interface{}{"name": "used"},
+		},
+	}}
+	cli := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		chaincodeGVR: "ChaincodeList",
+	}, NewEndorsePolicy("used", "ch1", policy, ""), NewEndorsePolicy("unused", "ch1", policy, ""), cc)
+
+	assert.EqualError(t, CheckDeletable(context.Background(), cli, "used"), "endorsepolicy used is still used by chaincode ch1-basic")
+	assert.NoError(t, CheckDeletable(context.Background(), cli, "unused"))
+	assert.Error(t, CheckDeletable(context.Background(), cli, "unknown"))
+}
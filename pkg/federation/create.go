@@ -0,0 +1,103 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+var federationGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.FederationResource}
+
+// NewFederation builds the Federation CR initiated by initiator with members
+func NewFederation(name string, initiator string, members []string, policy string, description string) *unstructured.Unstructured {
+	specMembers := []interface{}{
+		map[string]interface{}{"name": initiator, "initiator": true},
+	}
+	for _, member := range utils.RemoveDuplicateForStringSlice(members) {
+		if member == initiator {
+			continue
+		}
+		specMembers = append(specMembers, map[string]interface{}{"name": member})
+	}
+	spec := map[string]interface{}{
+		"license": map[string]interface{}{
+			"accept": true,
+		},
+		"policy":  policy,
+		"members": specMembers,
+	}
+	if description != "" {
+		spec["description"] = description
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "Federation",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": spec,
+	}}
+}
+
+func NewFedCreateCmd(option common.Options) *cobra.Command {
+	var (
+		options     proposal.Options
+		members     []string
+		description string
+	)
+	cmd := &cobra.Command{
+		Use:   "fed NAME --members ORG,ORG [--initiator ORG] [--policy All|Majority|OneVoteVeto]",
+		Short: "Create a federation and the proposal for its members to vote",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := proposal.ValidatePolicy(options.Policy); err != nil {
+				return err
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if options.Initiator == "" {
+				if options.Initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+					return err
+				}
+			}
+
+			fed := NewFederation(name, options.Initiator, members, options.Policy, description)
+			p := proposal.NewProposal(options, proposal.SourceCreateFederation, map[string]interface{}{
+				"federation": name,
+			})
+			return proposal.CreateWithProposal(cmd.Context(), cli, option.Out, federationGVR, fed, p)
+		},
+	}
+
+	proposal.AddFlags(cmd, &options)
+	cmd.Flags().StringVar(&options.Policy, "policy", proposal.PolicyAll, "policy of the federation to pass proposals, one of All, Majority and OneVoteVeto")
+	cmd.Flags().StringSliceVar(&members, "members", nil, "organizations invited to the federation")
+	cmd.Flags().StringVar(&description, "description", "", "description of the federation")
+	return cmd
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/bestchains/bc-cli/pkg/relation"
+)
+
+func TestNewFederation(t *testing.T) {
+	fed := NewFederation("fed1", "org1", []string{"org2", "org1", "org3", "org2"}, "Majority", "")
+	assert.Equal(t, []string{"org1", "org2", "org3"}, relation.Members(fed))
+
+	members, _, _ := unstructured.NestedSlice(fed.Object, "spec", "members")
+	assert.Equal(t, map[string]interface{}{"name": "org1", "initiator": true}, members[0])
+	policy, _, _ := unstructured.NestedString(fed.Object, "spec", "policy")
+	assert.Equal(t, "Majority", policy)
+	_, found, _ := unstructured.NestedString(fed.Object, "spec", "description")
+	assert.False(t, found)
+}
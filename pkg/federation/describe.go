@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+func describeDetails(w describe.PrefixWriter, fed *unstructured.Unstructured) {
+	policy := utils.GetNestedString(fed.Object, "spec", "policy")
+	if policy == "" {
+		policy = proposal.PolicyAll
+	}
+	w.Write(describe.LEVEL_0, "Name:\t%s\n", fed.GetName())
+	w.Write(describe.LEVEL_0, "Policy:\t%s\n", policy)
+	w.Write(describe.LEVEL_0, "Members:\t%s\n", relation.FormatMembers(fed))
+	relation.WriteCommon(w, fed)
+}
+
+func NewFedDescribeCmd(option common.Options) *cobra.Command {
+	return relation.NewDescribeCmd(option, relation.KindFederation, &cobra.Command{
+		Use:     "fed NAME...",
+		Aliases: []string{"federation"},
+		Short:   "Show details of federations with their members, networks, channels and events",
+	}, describeDetails)
+}
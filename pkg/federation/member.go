@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federation
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// NewFedCmd returns the command to manage the lifecycle of federations
+func NewFedCmd(option common.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fed",
+		Short: "Manage members of federations and dissolve them through proposals",
+	}
+	cmd.AddCommand(newAddMemberCmd(option))
+	cmd.AddCommand(newRemoveMemberCmd(option))
+	cmd.AddCommand(newDissolveCmd(option))
+	return cmd
+}
+
+// federationProposalCmd builds a command which creates a proposal of an existing federation.
+// source returns the proposal source of the federation or an error if the request is invalid.
+func federationProposalCmd(option common.Options, cmd *cobra.Command, sourceType string, source func(fed *unstructured.Unstructured) (map[string]interface{}, error)) *cobra.Command {
+	var options proposal.Options
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cli, err := common.GetDynamicClient()
+		if err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		fed, err := cli.Resource(federationGVR).Get(cmd.Context(), args[0], v1.GetOptions{})
+		if err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		s, err := source(fed)
+		if err != nil {
+			return err
+		}
+		if options.Initiator == "" {
+			if options.Initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+				return err
+			}
+		}
+		if !utils.ContainsString(relation.Members(fed), options.Initiator) {
+			return fmt.Errorf("initiator %s is not a member of federation %s", options.Initiator, fed.GetName())
+		}
+		// proposals of a federation are passed by the policy of the federation
+		options.Policy, _, _ = unstructured.NestedString(fed.Object, "spec", "policy")
+		if options.Policy == "" {
+			options.Policy = proposal.PolicyAll
+		}
+		return proposal.CreateAndReport(cmd.Context(), cli, option.Out, proposal.NewProposal(options, sourceType, s))
+	}
+	proposal.AddFlags(cmd, &options)
+	return cmd
+}
+
+func newAddMemberCmd(option common.Options) *cobra.Command {
+	var members []string
+	cmd := &cobra.Command{
+		Use:   "add-member FED --members ORG,ORG",
+		Short: "Propose to add organizations to a federation",
+	}
+	cmd = federationProposalCmd(option, cmd, proposal.SourceAddMember, func(fed *unstructured.Unstructured) (map[string]interface{}, error) {
+		members = utils.RemoveDuplicateForStringSlice(members)
+		if len(members) == 0 {
+			return nil, fmt.Errorf("no members provided")
+		}
+		current := relation.Members(fed)
+		added := make([]interface{}, 0, len(members))
+		for _, member := range members {
+			if utils.ContainsString(current, member) {
+				return nil, fmt.Errorf("organization %s is already a member of federation %s", member, fed.GetName())
+			}
+			added = append(added, member)
+		}
+		return map[string]interface{}{"federation": fed.GetName(), "members": added}, nil
+	})
+	cmd.Flags().StringSliceVar(&members, "members", nil, "organizations to add")
+	return cmd
+}
+
+func newRemoveMemberCmd(option common.Options) *cobra.Command {
+	var member string
+	cmd := &cobra.Command{
+		Use:   "remove-member FED --member ORG",
+		Short: "Propose to remove an organization from a federation",
+	}
+	cmd = federationProposalCmd(option, cmd, proposal.SourceDeleteMember, func(fed *unstructured.Unstructured) (map[string]interface{}, error) {
+		if member == "" {
+			return nil, fmt.Errorf("no member provided")
+		}
+		if !utils.ContainsString(relation.Members(fed), member) {
+			return nil, fmt.Errorf("organization %s is not a member of federation %s", member, fed.GetName())
+		}
+		return map[string]interface{}{"federation": fed.GetName(), "member": member}, nil
+	})
+	cmd.Flags().StringVar(&member, "member", "", "organization to remove")
+	return cmd
+}
+
+func newDissolveCmd(option common.Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dissolve FED",
+		Short: "Propose to dissolve a federation",
+	}
+	return federationProposalCmd(option, cmd, proposal.SourceDissolveFederation, func(fed *unstructured.Unstructured) (map[string]interface{}, error) {
+		return map[string]interface{}{"federation": fed.GetName()}, nil
+	})
+}
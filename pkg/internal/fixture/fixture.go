@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fixture provides the resources and the fake dynamic client shared by the tests of the commands.
+package fixture
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+// listKinds are the list kinds of the resources which are listed by the commands
+var listKinds = map[schema.GroupVersionResource]string{
+	ibpGVR(common.OrganizationResource):               "OrganizationList",
+	ibpGVR(common.FederationResource):                 "FederationList",
+	ibpGVR(common.NetworkResource):                    "NetworkList",
+	ibpGVR(common.Channel):                            "ChannelList",
+	ibpGVR(common.Chaincode):                          "ChaincodeList",
+	ibpGVR(common.ChaincodeBuild):                     "ChaincodeBuildList",
+	ibpGVR(common.EndorsePolicy):                      "EndorsePolicyList",
+	ibpGVR(common.Proposal):                           "ProposalList",
+	ibpGVR(common.Vote):                               "VoteList",
+	{Version: common.CoreVersion, Resource: "events"}: "EventList",
+}
+
+func ibpGVR(resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: resource}
+}
+
+// NewObject returns a cluster scoped resource of kind in the ibp.com group
+func NewObject(kind string, name string, spec map[string]interface{}, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name},
+		"spec":       spec,
+		"status":     status,
+	}}
+}
+
+// Members returns the spec.members of a federation, network or channel with names
+func Members(names ...string) []interface{} {
+	result := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		result = append(result, map[string]interface{}{"name": name})
+	}
+	return result
+}
+
+// NewClient returns a fake dynamic client holding objects, which can list every resource used by the commands
+func NewClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)
+}
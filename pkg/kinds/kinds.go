@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kinds registers the kinds of bestchains resources which bc-cli gets, waits for and so on.
+package kinds
+
+import (
+	"context"
+
+	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/chaincode"
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/channel"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/endorsepolicy"
+	"github.com/bestchains/bc-cli/pkg/federation"
+	"github.com/bestchains/bc-cli/pkg/network"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/resource"
+	"github.com/bestchains/bc-cli/pkg/vote"
+)
+
+// Peer is the kind of peers, which are in the namespaces of the organizations of the current user
+var Peer = resource.Kind{
+	Kind:    "IBPPeer",
+	Name:    "peer",
+	Aliases: []string{"peers"},
+	GVR:     schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.IBPPeer},
+	Namespaces: func(ctx context.Context, cli dynamic.Interface) ([]string, error) {
+		return org.ListUserOrganizations(cli, viper.GetString("auth.username"))
+	},
+	Columns: []resource.Column{
+		{Name: "Organization", Value: func(obj *unstructured.Unstructured) interface{} { return obj.GetNamespace() }},
+		{Name: "Status", Value: func(obj *unstructured.Unstructured) interface{} { return resource.OrNone(common.StatusType(obj)) }},
+	},
+}
+
+// Registry has all kinds of bestchains resources
+var Registry = resource.NewRegistry(
+	org.Kind,
+	federation.Kind,
+	network.Kind,
+	channel.Kind,
+	chaincode.Kind,
+	chaincodebuild.Kind,
+	endorsepolicy.Kind,
+	proposal.Kind,
+	vote.Kind,
+	Peer,
+)
@@ -17,15 +17,14 @@ limitations under the License.
 package repository
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net/http"
-	"net/url"
+	"strings"
 
 	"github.com/bestchains/bc-cli/pkg/account"
+	"github.com/bestchains/bc-cli/pkg/client/market"
 	"github.com/bestchains/bc-cli/pkg/common"
-	"github.com/bestchains/bc-cli/pkg/nonce"
-	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -47,11 +46,17 @@ func NewCreateMarketRepoCmd() *cobra.Command {
 				return err
 			}
 
-			// read repository url
-			repoURL, err := cmd.Flags().GetString("url")
+			// read repository url, --url is the deprecated name of --repo-url
+			repoURL, err := cmd.Flags().GetString("repo-url")
 			if err != nil {
 				return err
 			}
+			if repoURL == "" {
+				repoURL, _ = cmd.Flags().GetString("url")
+			}
+			if repoURL == "" {
+				return fmt.Errorf("--repo-url is required")
+			}
 
 			// bind depository server to flag
 			_ = viper.BindPFlag("saas.market.server", cmd.Flags().Lookup("host"))
@@ -61,11 +66,16 @@ func NewCreateMarketRepoCmd() *cobra.Command {
 			}
 
 			fmt.Printf("creating repository with account %s endorsement \n", accountAddress)
-			resp, err := CreateRepo(host, walletDir, accountAddress, repoURL)
+			resp, err := CreateRepo(cmd.Context(), host, walletDir, accountAddress, repoURL)
 			if err != nil {
 				return err
 			}
-			fmt.Print(string(resp))
+			if resp.ID == "" {
+				// print the response as is if it is not in the known shape
+				fmt.Println(strings.TrimSpace(string(resp.Raw)))
+				return nil
+			}
+			fmt.Printf("repository/%s created\n", resp.ID)
 			return nil
 
 		},
@@ -76,23 +86,21 @@ func NewCreateMarketRepoCmd() *cobra.Command {
 	cmd.Flags().StringP("wallet", "w", common.DefaultWalletConfigDir, "wallet path")
 	cmd.Flags().StringP("account", "a", "", "account to be used")
 	cmd.Flags().String("repo-url", "", "repository url")
+	cmd.Flags().String("url", "", "repository url")
+	_ = cmd.Flags().MarkDeprecated("url", "use --repo-url instead")
 
 	// define required flags
 	err = cmd.MarkFlagRequired("account")
 	if err != nil {
 		log.Fatal(err)
 	}
-	err = cmd.MarkFlagRequired("repo-url")
-	if err != nil {
-		log.Fatal(err)
-	}
 
 	return cmd
 }
 
 // CreateRepo creates a new repository on the specified host using the provided account and repo URL.
-// It returns the response body as a byte slice and any error encountered.
-func CreateRepo(host string, walletDir string, accountAddress string, repoURL string) ([]byte, error) {
+// It returns the response of the market server and any error encountered.
+func CreateRepo(ctx context.Context, host string, walletDir string, accountAddress string, repoURL string) (*market.CreateRepoResponse, error) {
 	// Read account info.
 	wallet, err := account.NewLocalWallet(walletDir)
 	if err != nil {
@@ -103,30 +111,9 @@ func CreateRepo(host string, walletDir string, accountAddress string, repoURL st
 		return nil, err
 	}
 
-	// Get nonce.
-	currNonce, err := nonce.Get(host, common.MarketCurrentNonce, acc.Address)
-	if err != nil {
-		return nil, err
-	}
-
-	// Generate message.
-	msgBase64, err := acc.GenerateAndSignMessage(currNonce, repoURL)
-	if err != nil {
-		return nil, err
-	}
-
-	// POST PutValue request.
-	postValue := url.Values{}
-	postValue.Add("message", msgBase64)
-	postValue.Add("url", repoURL)
-
-	host = fmt.Sprintf("%s%s", host, common.CreateRepository)
-	resp, err := uhttp.Do(host, http.MethodPost, map[string]string{
-		"Content-Type": "application/x-www-form-urlencoded",
-	}, []byte(postValue.Encode()))
+	client, err := market.NewClient(host)
 	if err != nil {
 		return nil, err
 	}
-
-	return resp, nil
+	return client.CreateRepo(ctx, acc, repoURL)
 }
@@ -0,0 +1,229 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+var (
+	networkGVR    = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Network}
+	federationGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.FederationResource}
+)
+
+// OrdererSpec is the spec of orderers of a network
+type OrdererSpec struct {
+	// Count is the number of orderer nodes in the raft cluster
+	Count int
+	// CPU and Memory are the requested resources of each orderer
+	CPU    string
+	Memory string
+	// Storage is the size of the volume of each orderer, stored in StorageClass
+	Storage      string
+	StorageClass string
+}
+
+// ParseOrdererSpec parses spec like cpu=500m,memory=1Gi,storage=10Gi,storage-class=standard into s
+func ParseOrdererSpec(spec string, s *OrdererSpec) error {
+	if spec == "" {
+		return nil
+	}
+	for _, kv := range strings.Split(spec, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(kv), "=")
+		if !ok || v == "" {
+			return fmt.Errorf("invalid orderer spec %q, must be key=value", kv)
+		}
+		switch k {
+		case "cpu", "memory", "storage":
+			if _, err := resource.ParseQuantity(v); err != nil {
+				return fmt.Errorf("invalid %s %q: %w", k, v, err)
+			}
+		}
+		switch k {
+		case "cpu":
+			s.CPU = v
+		case "memory":
+			s.Memory = v
+		case "storage":
+			s.Storage = v
+		case "storage-class":
+			s.StorageClass = v
+		default:
+			return fmt.Errorf("unknown orderer spec %q, must be one of cpu, memory, storage and storage-class", k)
+		}
+	}
+	return nil
+}
+
+// NewNetwork builds the Network CR of federation fed initiated by initiator with members
+func NewNetwork(name string, fed string, initiator string, members []string, orderer OrdererSpec, description string) *unstructured.Unstructured {
+	specMembers := []interface{}{
+		map[string]interface{}{"name": initiator, "initiator": true},
+	}
+	for _, member := range utils.RemoveDuplicateForStringSlice(members) {
+		if member != initiator {
+			specMembers = append(specMembers, map[string]interface{}{"name": member})
+		}
+	}
+	orderSpec := map[string]interface{}{
+		"license": map[string]interface{}{
+			"accept": true,
+		},
+		"clusterSize": int64(orderer.Count),
+		"ordererType": "etcdraft",
+	}
+	requests := map[string]interface{}{}
+	if orderer.CPU != "" {
+		requests["cpu"] = orderer.CPU
+	}
+	if orderer.Memory != "" {
+		requests["memory"] = orderer.Memory
+	}
+	if len(requests) > 0 {
+		orderSpec["resources"] = map[string]interface{}{
+			"orderer": map[string]interface{}{"requests": requests},
+		}
+	}
+	storage := map[string]interface{}{}
+	if orderer.Storage != "" {
+		storage["size"] = orderer.Storage
+	}
+	if orderer.StorageClass != "" {
+		storage["class"] = orderer.StorageClass
+	}
+	if len(storage) > 0 {
+		orderSpec["storage"] = map[string]interface{}{"orderer": storage}
+	}
+
+	spec := map[string]interface{}{
+		"license": map[string]interface{}{
+			"accept": true,
+		},
+		"federation": fed,
+		"members":    specMembers,
+		"orderSpec":  orderSpec,
+	}
+	if description != "" {
+		spec["description"] = description
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "Network",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": spec,
+	}}
+}
+
+// printManifests prints objs as yaml documents
+func printManifests(out io.Writer, objs ...*unstructured.Unstructured) error {
+	p := &printers.YAMLPrinter{}
+	for _, obj := range objs {
+		if err := p.PrintObj(obj, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewNetworkCreateCmd(option common.Options) *cobra.Command {
+	var (
+		options     proposal.Options
+		fed         string
+		members     []string
+		orderer     OrdererSpec
+		ordererSpec string
+		description string
+		dryRun      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "network NAME --fed FED [--initiator ORG] [--members ORG,ORG] [--orderer-count N] [--orderer-spec cpu=500m,memory=1Gi,storage=10Gi]",
+		Short: "Create a network of a federation and the proposal for its members to vote",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if orderer.Count != 1 && orderer.Count != 3 && orderer.Count != 5 {
+				return fmt.Errorf("invalid orderer count %d, must be 1, 3 or 5", orderer.Count)
+			}
+			if err := ParseOrdererSpec(ordererSpec, &orderer); err != nil {
+				return err
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fedObj, err := cli.Resource(federationGVR).Get(cmd.Context(), fed, v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fedMembers := relation.Members(fedObj)
+			if options.Initiator == "" {
+				if options.Initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+					return err
+				}
+			}
+			if len(members) == 0 {
+				members = fedMembers
+			}
+			for _, member := range append([]string{options.Initiator}, members...) {
+				if !utils.ContainsString(fedMembers, member) {
+					return fmt.Errorf("organization %s is not a member of federation %s", member, fed)
+				}
+			}
+			options.Policy, _, _ = unstructured.NestedString(fedObj.Object, "spec", "policy")
+			if options.Policy == "" {
+				options.Policy = proposal.PolicyAll
+			}
+
+			network := NewNetwork(name, fed, options.Initiator, members, orderer, description)
+			p := proposal.NewProposal(options, proposal.SourceCreateNetwork, map[string]interface{}{
+				"network": name,
+			})
+			if dryRun {
+				return printManifests(option.Out, network, p)
+			}
+			return proposal.CreateWithProposal(cmd.Context(), cli, option.Out, networkGVR, network, p)
+		},
+	}
+
+	proposal.AddFlags(cmd, &options)
+	cmd.Flags().StringVar(&fed, "fed", "", "federation of the network")
+	cmd.Flags().StringSliceVar(&members, "members", nil, "organizations of the network, default to all members of the federation")
+	cmd.Flags().IntVar(&orderer.Count, "orderer-count", 1, "number of orderer nodes, one of 1, 3 and 5")
+	cmd.Flags().StringVar(&ordererSpec, "orderer-spec", "", "resources of each orderer node, such as cpu=500m,memory=1Gi,storage=10Gi,storage-class=standard")
+	cmd.Flags().StringVar(&description, "description", "", "description of the network")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only print the manifests which would be submitted")
+	_ = cmd.MarkFlagRequired("fed")
+	return cmd
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+)
+
+func TestParseOrdererSpec(t *testing.T) {
+	s := OrdererSpec{Count: 3}
+	assert.NoError(t, ParseOrdererSpec("cpu=500m, memory=1Gi,storage=10Gi,storage-class=standard", &s))
+	assert.Equal(t, OrdererSpec{Count: 3, CPU: "500m", Memory: "1Gi", Storage: "10Gi", StorageClass: "standard"}, s)
+
+	assert.Error(t, ParseOrdererSpec("cpu", &s))
+	assert.Error(t, ParseOrdererSpec("cpu=lots", &s))
+	assert.Error(t, ParseOrdererSpec("gpu=1", &s))
+}
+
+func TestNewNetwork(t *testing.T) {
+	network := NewNetwork("net1", "fed1", "org1", []string{"org1", "org2"}, OrdererSpec{Count: 3, CPU: "1"}, "")
+	assert.Equal(t, []string{"org1", "org2"}, relation.Members(network))
+	size, _, _ := unstructured.NestedInt64(network.Object, "spec", "orderSpec", "clusterSize")
+	assert.Equal(t, int64(3), size)
+	cpu, _, _ := unstructured.NestedString(network.Object, "spec", "orderSpec", "resources", "orderer", "requests", "cpu")
+	assert.Equal(t, "1", cpu)
+	_, found, _ := unstructured.NestedMap(network.Object, "spec", "orderSpec", "storage")
+	assert.False(t, found)
+
+	p := proposal.NewProposal(proposal.Options{Name: "pro1", Initiator: "org1"}, proposal.SourceCreateNetwork, map[string]interface{}{"network": "net1"})
+	out := new(bytes.Buffer)
+	assert.NoError(t, printManifests(out, network, p))
+	assert.Contains(t, out.String(), "kind: Network\n")
+	assert.Contains(t, out.String(), "\n---\n")
+	assert.Contains(t, out.String(), "kind: Proposal\n")
+}
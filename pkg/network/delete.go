@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+func NewNetworkDeleteCmd(option common.Options) *cobra.Command {
+	var (
+		options proposal.Options
+		dryRun  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "network NAME [--initiator ORG] [--dry-run]",
+		Short: "Propose to dissolve a network",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			network, err := cli.Resource(networkGVR).Get(cmd.Context(), args[0], v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if options.Initiator == "" {
+				if options.Initiator, err = proposal.DefaultInitiator(cmd.Context(), cli); err != nil {
+					return err
+				}
+			}
+			if !utils.ContainsString(relation.Members(network), options.Initiator) {
+				return fmt.Errorf("initiator %s is not a member of network %s", options.Initiator, network.GetName())
+			}
+			// networks are dissolved by the policy of their federation
+			options.Policy = proposal.FederationPolicy(cmd.Context(), cli, utils.GetNestedString(network.Object, "spec", "federation"))
+
+			p := proposal.NewProposal(options, proposal.SourceDissolveNetwork, map[string]interface{}{
+				"network": network.GetName(),
+			})
+			if dryRun {
+				return printManifests(option.Out, p)
+			}
+			return proposal.CreateAndReport(cmd.Context(), cli, option.Out, p)
+		},
+	}
+	proposal.AddFlags(cmd, &options)
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only print the manifest of the proposal which would be submitted")
+	return cmd
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+func describeDetails(w describe.PrefixWriter, network *unstructured.Unstructured) {
+	w.Write(describe.LEVEL_0, "Name:\t%s\n", network.GetName())
+	w.Write(describe.LEVEL_0, "Federation:\t%s\n", utils.GetNestedString(network.Object, "spec", "federation"))
+	w.Write(describe.LEVEL_0, "Members:\t%s\n", relation.FormatMembers(network))
+	if size, found, _ := unstructured.NestedInt64(network.Object, "spec", "orderSpec", "clusterSize"); found {
+		w.Write(describe.LEVEL_0, "Orderers:\t%d (%s)\n", size, utils.GetNestedString(network.Object, "spec", "orderSpec", "ordererType"))
+	}
+	relation.WriteCommon(w, network)
+}
+
+func NewNetworkDescribeCmd(option common.Options) *cobra.Command {
+	return relation.NewDescribeCmd(option, relation.KindNetwork, &cobra.Command{
+		Use:     "network NAME...",
+		Aliases: []string{"net"},
+		Short:   "Show details of networks with their federation, members, channels, chaincodes, endorsepolicies and events",
+	}, describeDetails)
+}
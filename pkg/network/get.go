@@ -18,113 +18,84 @@ package network
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
+	"github.com/spf13/viper"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/kubectl/pkg/cmd/get"
 
 	"github.com/bestchains/bc-cli/pkg/common"
 	"github.com/bestchains/bc-cli/pkg/federation"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/resource"
 	"github.com/bestchains/bc-cli/pkg/utils"
 )
 
+// Kind is the kind of networks, which are visible to the members of their federations
+var Kind = resource.Kind{
+	Kind:    relation.KindNetwork,
+	Name:    "network",
+	Aliases: []string{"net", "nets"},
+	GVR:     schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Network},
+	Visible: func(ctx context.Context, cli dynamic.Interface) (func(*unstructured.Unstructured) bool, error) {
+		orgNames, err := org.ListUserOrganizations(cli, viper.GetString("auth.username"))
+		if err != nil {
+			return nil, err
+		}
+		return VisibleTo(ctx, cli, orgNames), nil
+	},
+	Columns: []resource.Column{
+		{Name: "Federation", Value: resource.Field("spec", "federation")},
+		{Name: "Initiator", Value: func(obj *unstructured.Unstructured) interface{} { return resource.OrNone(relation.Initiator(obj)) }},
+		{Name: "Orderers", Value: func(obj *unstructured.Unstructured) interface{} {
+			size, _, _ := unstructured.NestedInt64(obj.Object, "spec", "orderSpec", "clusterSize")
+			return size
+		}},
+		{Name: "Channels", Value: resource.Count("status", "channels")},
+		{Name: "Status", Value: func(obj *unstructured.Unstructured) interface{} { return resource.OrNone(relation.Status(obj)) }},
+		{Name: "Members", Wide: true, Value: func(obj *unstructured.Unstructured) interface{} { return relation.FormatMembers(obj) }},
+	},
+}
+
 func NewNetworkGetCmd(option common.Options) *cobra.Command {
-	defaultPrintFlag := get.NewGetPrintFlags()
+	flags := resource.NewGetFlags()
 	cmd := &cobra.Command{
 		Use:   "network [NAME]",
 		Short: "Get a list of network",
-		Run: func(cmd *cobra.Command, args []string) {
-			cli, err := common.GetDynamicClient()
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return
-			}
-
-			list := corev1.List{
-				TypeMeta: v1.TypeMeta{
-					Kind:       "List",
-					APIVersion: "v1",
-				},
-				ListMeta: v1.ListMeta{},
-			}
-			var obj runtime.Object
-			if len(args) == 0 {
-				networks, err := ListNetworks(cli)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				for i := 0; i < len(networks.Items); i++ {
-					list.Items = append(list.Items, runtime.RawExtension{Object: &networks.Items[i]})
-				}
-			} else {
-				for _, arg := range utils.RemoveDuplicateForStringSlice(args) {
-					network, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Network}).Get(context.TODO(), arg, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						continue
-					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: network})
-				}
-			}
+		Long: `Get a list of network.
 
-			if len(list.Items) != 1 {
-				listData, err := json.Marshal(list)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, listData)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				obj = converted
-			} else {
-				obj = list.Items[0].Object
-			}
-
-			p, err := defaultPrintFlag.ToPrinter()
+Networks are visible to the organizations of the current user which are in spec.members of their federations,
+including federations whose proposals are still being voted on.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			cli, err := common.GetDynamicClient()
 			if err != nil {
 				fmt.Fprintln(option.ErrOut, err)
-				return
+				return err
 			}
-			_ = p.PrintObj(obj, option.Out)
+			return flags.Run(cmd.Context(), cli, option, Kind, resource.Options{Names: args})
 		},
 	}
-	defaultPrintFlag.AddFlags(cmd)
+	flags.AddFlags(cmd)
 	return cmd
 }
 
-// ListNetworks return a list of networks.
-// Return error if any error occurs.
-func ListNetworks(cli dynamic.Interface) (*unstructured.UnstructuredList, error) {
-	federations, err := federation.ListFederations(cli)
-	if err != nil {
-		return nil, err
-	}
-	var networkNames []string
-	for _, federation := range federations.Items {
-		networks, found, err := unstructured.NestedStringSlice(federation.Object, "status", "networks")
-		if !found || err != nil {
-			continue
-		}
-		networkNames = append(networkNames, networks...)
-	}
-	list := &unstructured.UnstructuredList{}
-	for _, networkName := range utils.RemoveDuplicateForStringSlice(networkNames) {
-		network, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Network}).Get(context.TODO(), networkName, v1.GetOptions{})
-		if err != nil {
-			continue
+// VisibleTo returns a filter keeping networks of federations which have any of organizations in spec.members.
+// Whether a federation is visible is looked up once and cached, so each federation is fetched at most once.
+func VisibleTo(ctx context.Context, cli dynamic.Interface, organizations []string) func(*unstructured.Unstructured) bool {
+	visible := federation.VisibleTo(organizations)
+	feds := make(map[string]bool)
+	return func(network *unstructured.Unstructured) bool {
+		fedName := utils.GetNestedString(network.Object, "spec", "federation")
+		if ok, cached := feds[fedName]; cached {
+			return ok
 		}
-		list.Items = append(list.Items, *network)
+		fed, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.FederationResource}).Get(ctx, fedName, v1.GetOptions{})
+		feds[fedName] = err == nil && visible(fed)
+		return feds[fedName]
 	}
-	return list, nil
 }
@@ -0,0 +1,51 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+
+	"github.com/bestchains/bc-cli/pkg/federation"
+)
+
+func TestVisibleTo(t *testing.T) {
+	cli := fake.NewSimpleDynamicClient(runtime.NewScheme(),
+		federation.NewFederation("fed1", "org1", []string{"org1", "org2"}, "", ""),
+		federation.NewFederation("fed2", "org3", []string{"org3"}, "", ""))
+	visible := VisibleTo(context.Background(), cli, []string{"org2"})
+
+	assert.True(t, visible(NewNetwork("net1", "fed1", "org1", nil, OrdererSpec{}, "")))
+	assert.False(t, visible(NewNetwork("net2", "fed2", "org3", nil, OrdererSpec{}, "")))
+	assert.False(t, visible(NewNetwork("net3", "fed3", "org1", nil, OrdererSpec{}, "")))
+
+	// each federation is fetched once, whether it is visible or not
+	assert.True(t, visible(NewNetwork("net4", "fed1", "org1", nil, OrdererSpec{}, "")))
+	assert.False(t, visible(NewNetwork("net5", "fed2", "org3", nil, OrdererSpec{}, "")))
+	assert.False(t, visible(NewNetwork("net6", "fed3", "org1", nil, OrdererSpec{}, "")))
+	gets := 0
+	for _, action := range cli.Actions() {
+		if action.GetVerb() == "get" {
+			gets++
+		}
+	}
+	assert.Equal(t, 3, gets)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package nonce
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -29,7 +30,8 @@ type nonce struct {
 	Nonce uint64 `json:"nonce"`
 }
 
-func Get(host string, path string, account string) (uint64, error) {
+// Get returns the current nonce of account from the server.
+func Get(ctx context.Context, host string, path string, account string) (uint64, error) {
 	// Add the account parameter to the URL query
 	getReqValue := url.Values{}
 	getReqValue.Add("account", account)
@@ -38,7 +40,7 @@ func Get(host string, path string, account string) (uint64, error) {
 	host = fmt.Sprintf("%s%s?%s", host, path, getReqValue.Encode())
 
 	// Make the HTTP GET request
-	resp, err := uhttp.Do(host, http.MethodGet, nil, nil)
+	resp, err := uhttp.Do(ctx, host, http.MethodGet, nil, nil)
 	if err != nil {
 		return 0, err
 	}
@@ -17,6 +17,7 @@ limitations under the License.
 package nonce
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -43,7 +44,7 @@ func TestGet(t *testing.T) {
 	defer testServer.Close()
 
 	// Call the Get function with the test server as the host.
-	nonceValue, err := Get(testServer.URL, common.DepositoryCurrentNonce, testAccount)
+	nonceValue, err := Get(context.Background(), testServer.URL, common.DepositoryCurrentNonce, testAccount)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package objectstore uploads files to the s3 compatible object store of the platform,
+// or to a local directory which stands in for it.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
+)
+
+// Uploader uploads objects into buckets
+type Uploader interface {
+	// Upload stores data as object in bucket, an existing object is overwritten
+	Upload(ctx context.Context, bucket string, object string, data []byte) error
+}
+
+// NewUploader returns the uploader of the object store in cfg
+func NewUploader(cfg common.ObjectStore) (Uploader, error) {
+	u, err := url.Parse(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "file":
+		return &dirUploader{dir: u.Path}, nil
+	case "http", "https":
+		// uploads are only bound by the context, the default timeout of a request would cut off large sources.
+		// The object store is not the platform, so the ID token of the user is never sent to it.
+		options := uhttp.DefaultClientOptions
+		options.Timeout = 0
+		options.Anonymous = true
+		client, err := uhttp.NewClient(u.Scheme+"://"+u.Host, options)
+		if err != nil {
+			return nil, err
+		}
+		region := cfg.Region
+		if region == "" {
+			region = "us-east-1"
+		}
+		return &s3Uploader{
+			server:    strings.TrimSuffix(cfg.Server, "/"),
+			region:    region,
+			accessKey: cfg.AccessKey,
+			secretKey: cfg.SecretKey,
+			client:    client,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported object store %q, must be a http, https or file url", cfg.Server)
+}
+
+// dirUploader stores objects as files under dir/bucket
+type dirUploader struct {
+	dir string
+}
+
+func (d *dirUploader) Upload(_ context.Context, bucket string, object string, data []byte) error {
+	path := filepath.Join(d.dir, bucket, filepath.FromSlash(object))
+	if !strings.HasPrefix(path, filepath.Join(d.dir, bucket)+string(filepath.Separator)) {
+		return fmt.Errorf("invalid object name %q", object)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// s3Uploader puts objects with path style urls, signed by aws signature version 4 if credentials are set
+type s3Uploader struct {
+	server    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *uhttp.Client
+}
+
+func (s *s3Uploader) Upload(ctx context.Context, bucket string, object string, data []byte) error {
+	// object names such as chaincode ids and versions are escaped the way they are signed
+	u := s.server + canonicalURI("/"+bucket+"/"+object)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if s.accessKey != "" {
+		sum := sha256.Sum256(data)
+		signV4(req, s.accessKey, s.secretKey, s.region, hex.EncodeToString(sum[:]), time.Now())
+	}
+	resp, err := s.client.Send(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
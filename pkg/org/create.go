@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package org
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+var organizationGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.OrganizationResource}
+
+// CreateOptions are the fields of an organization to create
+type CreateOptions struct {
+	Name        string
+	Admin       string
+	DisplayName string
+	Description string
+}
+
+// NewOrganization builds the Organization CR of options
+func NewOrganization(options CreateOptions) *unstructured.Unstructured {
+	spec := map[string]interface{}{
+		"license": map[string]interface{}{
+			"accept": true,
+		},
+		"admin": options.Admin,
+	}
+	if options.DisplayName != "" {
+		spec["displayName"] = options.DisplayName
+	}
+	if options.Description != "" {
+		spec["description"] = options.Description
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "Organization",
+		"metadata": map[string]interface{}{
+			"name": options.Name,
+		},
+		"spec": spec,
+	}}
+}
+
+// CreateOrganization creates the organization of options
+func CreateOrganization(ctx context.Context, cli dynamic.Interface, options CreateOptions) (*unstructured.Unstructured, error) {
+	return cli.Resource(organizationGVR).Create(ctx, NewOrganization(options), v1.CreateOptions{})
+}
+
+func NewOrgCreateCmd(option common.Options) *cobra.Command {
+	var (
+		options CreateOptions
+		wait    bool
+		timeout time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "org NAME [--admin USER] [--display-name NAME] [--description TEXT]",
+		Short: "Create an organization and wait for it to be deployed",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.Name = args[0]
+			if options.Admin == "" {
+				options.Admin = viper.GetString("auth.username")
+			}
+			if options.Admin == "" {
+				return fmt.Errorf("no admin provided, use --admin or login first")
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if _, err := CreateOrganization(cmd.Context(), cli, options); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fmt.Fprintf(option.Out, "organization/%s created\n", options.Name)
+			if !wait {
+				return nil
+			}
+
+			fmt.Fprintf(option.Out, "waiting for organization/%s to be deployed...\n", options.Name)
+			if _, err := common.WaitForStatus(cmd.Context(), cli.Resource(organizationGVR), options.Name, timeout, common.StatusDeployed); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			fmt.Fprintf(option.Out, "organization/%s deployed\n", options.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Admin, "admin", "", "admin user of the organization, default to the current user")
+	cmd.Flags().StringVar(&options.DisplayName, "display-name", "", "display name of the organization")
+	cmd.Flags().StringVar(&options.Description, "description", "", "description of the organization")
+	cmd.Flags().BoolVar(&wait, "wait", true, "wait for the organization to be deployed")
+	cmd.Flags().DurationVar(&timeout, "timeout", 5*time.Minute, "the length of time to wait for the organization to be deployed, zero means wait forever")
+	return cmd
+}
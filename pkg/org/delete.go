@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package org
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// CheckDeletable returns an error if the organization is still a member of any federation
+func CheckDeletable(ctx context.Context, cli dynamic.Interface, name string) error {
+	org, err := cli.Resource(organizationGVR).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	feds, _, _ := unstructured.NestedStringSlice(org.Object, "status", "federations")
+	if len(feds) > 0 {
+		return fmt.Errorf("organization %s is still a member of federation %s, remove it from the federations first", name, strings.Join(feds, ","))
+	}
+	return nil
+}
+
+func NewOrgDeleteCmd(option common.Options) *cobra.Command {
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "org NAME...",
+		Short: "Delete organizations which are not members of any federation",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			var lastErr error
+			for _, name := range utils.RemoveDuplicateForStringSlice(args) {
+				if err := CheckDeletable(cmd.Context(), cli, name); err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					lastErr = err
+					continue
+				}
+				if !yes && !utils.Confirm(option.In, option.Out, fmt.Sprintf("Delete organization %s?", name)) {
+					fmt.Fprintf(option.Out, "organization/%s skipped\n", name)
+					continue
+				}
+				if err := cli.Resource(organizationGVR).Delete(cmd.Context(), name, v1.DeleteOptions{}); err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					lastErr = err
+					continue
+				}
+				fmt.Fprintf(option.Out, "organization/%s deleted\n", name)
+			}
+			return lastErr
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "delete without confirmation")
+	return cmd
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package org
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+func describeDetails(w describe.PrefixWriter, org *unstructured.Unstructured) {
+	w.Write(describe.LEVEL_0, "Name:\t%s\n", org.GetName())
+	if displayName := utils.GetNestedString(org.Object, "spec", "displayName"); displayName != "" {
+		w.Write(describe.LEVEL_0, "Display Name:\t%s\n", displayName)
+	}
+	w.Write(describe.LEVEL_0, "Admin:\t%s\n", utils.GetNestedString(org.Object, "spec", "admin"))
+	relation.WriteCommon(w, org)
+}
+
+func NewOrgDescribeCmd(option common.Options) *cobra.Command {
+	return relation.NewDescribeCmd(option, relation.KindOrganization, &cobra.Command{
+		Use:     "org NAME...",
+		Aliases: []string{"organization"},
+		Short:   "Show details of organizations with their federations, networks, channels and events",
+	}, describeDetails)
+}
@@ -20,93 +20,58 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
-	"k8s.io/kubectl/pkg/cmd/get"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 
 	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/resource"
 )
 
+// Kind is the kind of organizations
+var Kind = resource.Kind{
+	Kind:    relation.KindOrganization,
+	Name:    "organization",
+	Aliases: []string{"org", "orgs"},
+	GVR:     schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.OrganizationResource},
+	Columns: []resource.Column{
+		{Name: "Admin", Value: resource.Field("spec", "admin")},
+		{Name: "Federations", Value: resource.Count("status", "federations")},
+		{Name: "Status", Value: func(obj *unstructured.Unstructured) interface{} { return resource.OrNone(relation.Status(obj)) }},
+		{Name: "Display Name", Wide: true, Value: resource.Field("spec", "displayName")},
+	},
+}
+
 func NewOrgGetCmd(option common.Options) *cobra.Command {
 	var (
 		labelSelector string
 		fieldSelector string
 	)
 
-	defaultPrintFlag := get.NewGetPrintFlags()
+	flags := resource.NewGetFlags()
 	cmd := &cobra.Command{
 		Use:   "org [NAME]",
 		Short: "Get a list of organization",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
 			cli, err := common.GetDynamicClient()
 			if err != nil {
 				fmt.Fprintln(option.ErrOut, err)
 				return err
 			}
-
-			list := corev1.List{
-				TypeMeta: v1.TypeMeta{
-					Kind:       "List",
-					APIVersion: "v1",
-				},
-				ListMeta: v1.ListMeta{},
-			}
-			var obj runtime.Object
-			if len(args) == 0 {
-				orgs, err := ListOrganizations(cli, labelSelector, fieldSelector)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return err
-				}
-				for i := 0; i < len(orgs.Items); i++ {
-					list.Items = append(list.Items, runtime.RawExtension{Object: &orgs.Items[i]})
-				}
-			} else {
-				for _, arg := range args {
-					org, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.OrganizationResource}).Get(context.TODO(), arg, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						continue
-					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: org})
-				}
-			}
-
-			if len(list.Items) != 1 {
-				listData, err := json.Marshal(list)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return err
-				}
-				converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, listData)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return err
-				}
-				obj = converted
-			} else {
-				obj = list.Items[0].Object
-			}
-
-			p, err := defaultPrintFlag.ToPrinter()
-			if err != nil {
-				fmt.Fprintln(option.ErrOut, err)
-				return err
-			}
-			_ = p.PrintObj(obj, option.Out)
-			return nil
+			return flags.Run(cmd.Context(), cli, option, Kind, resource.Options{Names: args, LabelSelector: labelSelector, FieldSelector: fieldSelector})
 		},
 	}
 
-	defaultPrintFlag.AddFlags(cmd)
+	flags.AddFlags(cmd)
 	cmd.Flags().StringVar(&fieldSelector, "field-selector", "", "Selector (field query) to filter on, supports '=', '==', and '!='.(e.g. --field-selector key1=value1,key2=value2). The server only supports a limited number of field queries per type.")
 	cmdutil.AddLabelSelectorFlagVar(cmd, &labelSelector)
 
@@ -125,3 +90,48 @@ func ListOrganizations(cli dynamic.Interface, labelSelector string, fieldSelecto
 	}
 	return organizations, nil
 }
+
+// ListAdminOrganizations returns the names of organizations administered by username.
+func ListAdminOrganizations(cli dynamic.Interface, username string) ([]string, error) {
+	orgs, err := ListOrganizations(cli, fmt.Sprintf("bestchains.organization.admin=%s", username), "")
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(orgs.Items))
+	for _, o := range orgs.Items {
+		names = append(names, o.GetName())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListUserOrganizations returns the names of organizations the user belongs to,
+// which are recorded in the `bestchains` annotation of the IAM user.
+// Return error if the user or its organizations can not be found.
+func ListUserOrganizations(cli dynamic.Interface, username string) ([]string, error) {
+	users, err := cli.Resource(schema.GroupVersionResource{Group: common.IAMGroup, Version: common.IAMVersion, Resource: common.UserResource}).List(context.TODO(), v1.ListOptions{
+		LabelSelector: fmt.Sprintf("t7d.io.username=%s", username),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(users.Items) == 0 {
+		return nil, errors.New("No user found.")
+	}
+	user := users.Items[0]
+	orgList := user.GetAnnotations()["bestchains"]
+	var orgs map[string]interface{}
+	if err := json.Unmarshal([]byte(orgList), &orgs); err != nil {
+		return nil, err
+	}
+	orgNames, ok := orgs["list"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("No organization found.")
+	}
+	names := make([]string, 0, len(orgNames))
+	for name := range orgNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
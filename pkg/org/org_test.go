@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package org
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func newFakeClient(objs ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		organizationGVR: "OrganizationList",
+	}, objs...)
+}
+
+func TestCreateOrganization(t *testing.T) {
+	cli := newFakeClient()
+	_, err := CreateOrganization(context.Background(), cli, CreateOptions{Name: "org1", Admin: "alice", Description: "first org"})
+	assert.NoError(t, err)
+
+	org, err := cli.Resource(organizationGVR).Get(context.Background(), "org1", v1.GetOptions{})
+	assert.NoError(t, err)
+	admin, _, _ := unstructured.NestedString(org.Object, "spec", "admin")
+	assert.Equal(t, "alice", admin)
+	accept, _, _ := unstructured.NestedBool(org.Object, "spec", "license", "accept")
+	assert.True(t, accept)
+	_, found, _ := unstructured.NestedString(org.Object, "spec", "displayName")
+	assert.False(t, found)
+}
+
+func TestCheckDeletable(t *testing.T) {
+	member := NewOrganization(CreateOptions{Name: "member", Admin: "alice"})
+	assert.NoError(t, unstructured.SetNestedStringSlice(member.Object, []string{"fed1"}, "status", "federations"))
+	cli := newFakeClient(member, NewOrganization(CreateOptions{Name: "alone", Admin: "bob"}))
+
+	err := CheckDeletable(context.Background(), cli, "member")
+	assert.ErrorContains(t, err, "fed1")
+	assert.NoError(t, CheckDeletable(context.Background(), cli, "alone"))
+	assert.Error(t, CheckDeletable(context.Background(), cli, "unknown"))
+}
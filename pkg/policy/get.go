@@ -17,16 +17,279 @@ limitations under the License.
 package policy
 
 import (
-	"github.com/bestchains/bc-cli/pkg/common"
+	"context"
+	"fmt"
+	"sort"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubectl/pkg/cmd/get"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/endorsepolicy"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/printer"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+	"github.com/bestchains/bc-cli/pkg/resource"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// Types of governance policies
+const (
+	// TypeFederation is the voting policy of proposals in a federation
+	TypeFederation = "federation"
+	// TypeChannel is the voting policy of member changes of a channel, which follows the federation of its network
+	TypeChannel = "channel"
+	// TypeEndorsement is the endorsepolicy of chaincodes in a channel
+	TypeEndorsement = "endorsement"
 )
 
+var headers = []string{"name", "type", "scope", "rule", "threshold"}
+
+func gvr(resource string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: resource}
+}
+
+// Policy is a governance policy, Object is the resource which defines it
+type Policy struct {
+	Type      string
+	Scope     string
+	Rule      string
+	Threshold string
+	Object    *unstructured.Unstructured
+}
+
+var _ printer.Printer = Policy{}
+
+func (p Policy) GetByHeader(header string) string {
+	switch header {
+	case "name":
+		return p.Object.GetName()
+	case "type":
+		return p.Type
+	case "scope":
+		return p.Scope
+	case "rule":
+		return p.Rule
+	case "threshold":
+		return p.Threshold
+	}
+	return "<none>"
+}
+
+// votingPolicy returns the voting policy of policy among the members
+func votingPolicy(policyType string, scope string, policy string, members int, obj *unstructured.Unstructured) Policy {
+	if policy == "" {
+		policy = proposal.PolicyAll
+	}
+	threshold := fmt.Sprintf("%d of %d members", proposal.RequiredApprovals(policy, members), members)
+	if policy == proposal.PolicyOneVoteVeto {
+		threshold += ", any rejection vetoes"
+	}
+	return Policy{Type: policyType, Scope: scope, Rule: policy, Threshold: threshold, Object: obj}
+}
+
+// endorsementPolicy returns the endorsement policy defined by the endorsepolicy ep
+func endorsementPolicy(ep *unstructured.Unstructured) Policy {
+	value := utils.GetNestedString(ep.Object, "spec", "value")
+	p := Policy{
+		Type:      TypeEndorsement,
+		Scope:     "channel/" + utils.GetNestedString(ep.Object, "spec", "channel"),
+		Rule:      value,
+		Threshold: "<unknown>",
+		Object:    ep,
+	}
+	if parsed, err := endorsepolicy.Parse(value); err == nil {
+		p.Threshold = fmt.Sprintf("%d of %d organizations", parsed.MinOrganizations(), len(parsed.MSPIDs()))
+	}
+	return p
+}
+
+// ListPolicies returns the governance policies relevant to orgs: the voting policies of their federations,
+// the voting policies of the channels they are members of, and the endorsepolicies of those channels.
+// A resource which can not be got does not stop the others, the errors are returned as an aggregate
+// together with the policies found.
+func ListPolicies(ctx context.Context, cli dynamic.Interface, orgs []string) ([]Policy, error) {
+	var (
+		fedNames []string
+		errs     []error
+	)
+	for _, name := range orgs {
+		o, err := cli.Resource(gvr(common.OrganizationResource)).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		feds, _, _ := unstructured.NestedStringSlice(o.Object, "status", "federations")
+		fedNames = append(fedNames, feds...)
+	}
+
+	var policies []Policy
+	channelNames := map[string]bool{}
+	for _, fedName := range utils.RemoveDuplicateForStringSlice(fedNames) {
+		fed, err := cli.Resource(gvr(common.FederationResource)).Get(ctx, fedName, v1.GetOptions{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fedPolicy := utils.GetNestedString(fed.Object, "spec", "policy")
+		policies = append(policies, votingPolicy(TypeFederation, "federation/"+fedName, fedPolicy, len(relation.Members(fed)), fed))
+
+		networks, _, _ := unstructured.NestedStringSlice(fed.Object, "status", "networks")
+		for _, netName := range networks {
+			net, err := cli.Resource(gvr(common.NetworkResource)).Get(ctx, netName, v1.GetOptions{})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			channels, _, _ := unstructured.NestedStringSlice(net.Object, "status", "channels")
+			for _, chName := range channels {
+				if channelNames[chName] {
+					continue
+				}
+				ch, err := cli.Resource(gvr(common.Channel)).Get(ctx, chName, v1.GetOptions{})
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				members := relation.Members(ch)
+				if !containsAny(members, orgs) {
+					continue
+				}
+				channelNames[chName] = true
+				policies = append(policies, votingPolicy(TypeChannel, "channel/"+chName, fedPolicy, len(members), ch))
+			}
+		}
+	}
+
+	if len(channelNames) > 0 {
+		eps, err := cli.Resource(gvr(common.EndorsePolicy)).List(ctx, v1.ListOptions{})
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			for i := range eps.Items {
+				if channelNames[utils.GetNestedString(eps.Items[i].Object, "spec", "channel")] {
+					policies = append(policies, endorsementPolicy(&eps.Items[i]))
+				}
+			}
+		}
+	}
+
+	order := map[string]int{TypeFederation: 0, TypeChannel: 1, TypeEndorsement: 2}
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].Type != policies[j].Type {
+			return order[policies[i].Type] < order[policies[j].Type]
+		}
+		if policies[i].Scope != policies[j].Scope {
+			return policies[i].Scope < policies[j].Scope
+		}
+		return policies[i].Object.GetName() < policies[j].Object.GetName()
+	})
+	return policies, utilerrors.Reduce(utilerrors.NewAggregate(errs))
+}
+
+func containsAny(list []string, items []string) bool {
+	for _, item := range items {
+		if utils.ContainsString(list, item) {
+			return true
+		}
+	}
+	return false
+}
+
 func NewPolicyGetCmd(option common.Options) *cobra.Command {
+	var policyType string
+	defaultPrintFlag := get.NewGetPrintFlags()
 	cmd := &cobra.Command{
-		Use: "",
+		Use:   "policy [--type federation|channel|endorsement] [-o yaml]",
+		Short: "Get the governance policies relevant to the organizations of the current user",
+		Long: `Get the governance policies relevant to the organizations of the current user
+
+The voting policies of their federations, the voting policies of their channels which follow the federation
+of the network, and the endorsepolicies of their channels are listed. With -o yaml or -o json
+the resources which define the policies are printed.
+`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return nil
+			switch policyType {
+			case "", TypeFederation, TypeChannel, TypeEndorsement:
+			default:
+				return fmt.Errorf("unknown policy type %q, must be one of %s, %s and %s", policyType, TypeFederation, TypeChannel, TypeEndorsement)
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			orgs, err := org.ListUserOrganizations(cli, viper.GetString("auth.username"))
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			// the policies found are printed, the resources which can not be got still fail the command
+			policies, listErr := ListPolicies(cmd.Context(), cli, orgs)
+			if listErr != nil {
+				resource.PrintErrors(option, listErr)
+			}
+			if policyType != "" {
+				filtered := policies[:0]
+				for _, p := range policies {
+					if p.Type == policyType {
+						filtered = append(filtered, p)
+					}
+				}
+				policies = filtered
+			}
+
+			if format := *defaultPrintFlag.OutputFormat; format == "" || format == "wide" {
+				rows := make([]printer.Printer, 0, len(policies))
+				for _, p := range policies {
+					rows = append(rows, p)
+				}
+				printer.Print(option.Out, headers, rows)
+				return listErr
+			}
+
+			list := corev1.List{
+				TypeMeta: v1.TypeMeta{
+					Kind:       "List",
+					APIVersion: "v1",
+				},
+				ListMeta: v1.ListMeta{},
+			}
+			for _, p := range policies {
+				list.Items = append(list.Items, runtime.RawExtension{Object: p.Object})
+			}
+			var obj runtime.Object
+			if len(list.Items) != 1 {
+				obj, err = common.ListToObj(list)
+				if err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					return err
+				}
+			} else {
+				obj = list.Items[0].Object
+			}
+			p, err := defaultPrintFlag.ToPrinter()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if err := p.PrintObj(obj, option.Out); err != nil {
+				return err
+			}
+			return listErr
 		},
 	}
+	defaultPrintFlag.AddFlags(cmd)
+	cmd.Flags().StringVar(&policyType, "type", "", "only list policies of the type, one of federation, channel and endorsement")
 	return cmd
 }
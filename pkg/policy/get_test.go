@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/internal/fixture"
+	"github.com/bestchains/bc-cli/pkg/printer"
+)
+
+func TestListPolicies(t *testing.T) {
+	cli := fixture.NewClient(
+		fixture.NewObject("Organization", "org1", map[string]interface{}{}, map[string]interface{}{"federations": []interface{}{"fed1"}}),
+		fixture.NewObject("Federation", "fed1", map[string]interface{}{
+			"policy":  "Majority",
+			"members": fixture.Members("org1", "org2", "org3"),
+		}, map[string]interface{}{"networks": []interface{}{"net1"}}),
+		fixture.NewObject("Network", "net1", map[string]interface{}{}, map[string]interface{}{"channels": []interface{}{"ch1", "ch2"}}),
+		fixture.NewObject("Channel", "ch1", map[string]interface{}{"members": fixture.Members("org1", "org2")}, map[string]interface{}{}),
+		fixture.NewObject("Channel", "ch2", map[string]interface{}{"members": fixture.Members("org2", "org3")}, map[string]interface{}{}),
+		fixture.NewObject("EndorsePolicy", "ep1", map[string]interface{}{
+			"channel": "ch1",
+			"value":   "AND('org1.member', OR('org2.member', 'org3.member'))",
+		}, map[string]interface{}{}),
+		fixture.NewObject("EndorsePolicy", "ep2", map[string]interface{}{"channel": "ch2", "value": "'org2.member'"}, map[string]interface{}{}),
+	)
+
+	policies, err := ListPolicies(context.Background(), cli, []string{"org1"})
+	assert.NoError(t, err)
+	rows := make([]printer.Printer, 0, len(policies))
+	for _, p := range policies {
+		rows = append(rows, p)
+	}
+	out := &bytes.Buffer{}
+	printer.Print(out, headers, rows)
+	assert.Equal(t, `NAME    TYPE           SCOPE              RULE                                                    THRESHOLD
+fed1    federation     federation/fed1    Majority                                                2 of 3 members
+ch1     channel        channel/ch1        Majority                                                2 of 2 members
+ep1     endorsement    channel/ch1        AND('org1.member', OR('org2.member', 'org3.member'))    2 of 3 organizations
+`, out.String())
+
+	_, err = ListPolicies(context.Background(), cli, []string{"unknown"})
+	assert.Error(t, err)
+
+	// resources which can not be got are reported without dropping the policies found
+	policies, err = ListPolicies(context.Background(), cli, []string{"org1", "unknown"})
+	assert.ErrorContains(t, err, `"unknown" not found`)
+	assert.Len(t, policies, 3)
+	assert.NoError(t, cli.Resource(gvr(common.Channel)).Delete(context.Background(), "ch2", v1.DeleteOptions{}))
+	policies, err = ListPolicies(context.Background(), cli, []string{"org1"})
+	assert.ErrorContains(t, err, `"ch2" not found`)
+	assert.Len(t, policies, 3)
+}
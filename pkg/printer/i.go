@@ -36,6 +36,19 @@ func Print(output io.Writer, headers []string, objs []Printer) {
 	}
 
 	fmt.Fprintln(w, strings.Join(headersCopy, "\t"))
+	printRows(w, headers, objs)
+	w.Flush()
+}
+
+// PrintRows prints objs like Print without the header line,
+// it is used to print rows one by one after the header was printed
+func PrintRows(output io.Writer, headers []string, objs []Printer) {
+	w := tabwriter.NewWriter(output, 1, 1, 4, ' ', 0)
+	printRows(w, headers, objs)
+	w.Flush()
+}
+
+func printRows(w io.Writer, headers []string, objs []Printer) {
 	row := make([]string, len(headers))
 	for _, o := range objs {
 		for i := 0; i < len(headers); i++ {
@@ -43,5 +56,4 @@ func Print(output io.Writer, headers []string, objs []Printer) {
 		}
 		fmt.Fprintln(w, strings.Join(row, "\t"))
 	}
-	w.Flush()
 }
@@ -0,0 +1,297 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proposal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/org"
+)
+
+// Policies of proposals
+const (
+	PolicyAll         = "All"
+	PolicyMajority    = "Majority"
+	PolicyOneVoteVeto = "OneVoteVeto"
+)
+
+// Sources of proposals, only one of them is set in the spec of a proposal
+const (
+	SourceCreateFederation   = "createFederation"
+	SourceAddMember          = "addMember"
+	SourceDeleteMember       = "deleteMember"
+	SourceDissolveFederation = "dissolveFederation"
+	SourceCreateNetwork      = "createNetwork"
+	SourceDissolveNetwork    = "dissolveNetwork"
+	SourceUpdateChannel      = "updateChannelMember"
+	SourceDeployChaincode    = "deployChaincode"
+	SourceUpgradeChaincode   = "upgradeChaincode"
+)
+
+// Sources are all sources of proposals, in the order they are looked up in the spec of a proposal
+var Sources = []string{SourceCreateFederation, SourceAddMember, SourceDeleteMember, SourceDissolveFederation,
+	SourceCreateNetwork, SourceDissolveNetwork, SourceUpdateChannel, SourceDeployChaincode, SourceUpgradeChaincode}
+
+// Phases of proposals, Pending until the operator creates the votes
+const (
+	PhasePending  = "Pending"
+	PhaseVoting   = "Voting"
+	PhaseFinished = "Finished"
+)
+
+// Phases of votes in proposal status
+const (
+	VotePhaseCreated  = "Created"
+	VotePhaseVoted    = "Voted"
+	VotePhaseFinished = "Finished"
+)
+
+var (
+	proposalGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Proposal}
+	voteGVR     = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Vote}
+)
+
+// Options are the common fields of a proposal
+type Options struct {
+	// Name of the proposal, generated from the source if empty
+	Name string
+	// Initiator is the organization which initiates the proposal
+	Initiator string
+	// Policy decides how the votes pass the proposal
+	Policy string
+	// Duration is the time for organizations to vote
+	Duration time.Duration
+}
+
+// ValidatePolicy returns an error if policy is unknown
+func ValidatePolicy(policy string) error {
+	switch policy {
+	case PolicyAll, PolicyMajority, PolicyOneVoteVeto:
+		return nil
+	}
+	return fmt.Errorf("unknown policy %q, must be one of %s, %s, %s", policy, PolicyAll, PolicyMajority, PolicyOneVoteVeto)
+}
+
+// NewProposal builds a Proposal CR with source, source is the content of sourceType such as {"federation": "fed1"}
+func NewProposal(options Options, sourceType string, source map[string]interface{}) *unstructured.Unstructured {
+	now := time.Now()
+	name := options.Name
+	if name == "" {
+		name = GenerateName(sourceType)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "Proposal",
+		"metadata": map[string]interface{}{
+			"name": name,
+		},
+		"spec": map[string]interface{}{
+			"policy":    options.Policy,
+			"initiator": options.Initiator,
+			"startAt":   now.UTC().Format(time.RFC3339),
+			"endAt":     now.Add(options.Duration).UTC().Format(time.RFC3339),
+			sourceType:  source,
+		},
+	}}
+}
+
+// GenerateName returns a proposal name from sourceType, such as create-federation-x7k2p for createFederation.
+// Names must be DNS-1123 subdomains, so the camel case source is converted to lower case words,
+// and a random suffix keeps proposals of the same source from colliding.
+func GenerateName(sourceType string) string {
+	var b strings.Builder
+	for i, r := range sourceType {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return fmt.Sprintf("%s-%s", b.String(), utilrand.String(5))
+}
+
+// CreateProposal creates the proposal
+func CreateProposal(ctx context.Context, cli dynamic.Interface, proposal *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return cli.Resource(proposalGVR).Create(ctx, proposal, v1.CreateOptions{})
+}
+
+// DefaultInitiator returns the only organization administered by the current user,
+// an error is returned if the user administers none or more than one organizations.
+func DefaultInitiator(ctx context.Context, cli dynamic.Interface) (string, error) {
+	username := viper.GetString("auth.username")
+	names, err := org.ListAdminOrganizations(cli, username)
+	if err != nil {
+		return "", err
+	}
+	switch len(names) {
+	case 0:
+		return "", fmt.Errorf("user %s administers no organization", username)
+	case 1:
+		return names[0], nil
+	}
+	return "", fmt.Errorf("user %s administers organizations %v, choose one with --initiator", username, names)
+}
+
+// FederationPolicy returns the policy of federation fed, which is also the policy of proposals in it.
+// PolicyAll is returned if the federation has no policy or can not be found.
+func FederationPolicy(ctx context.Context, cli dynamic.Interface, fed string) string {
+	if fed == "" {
+		return PolicyAll
+	}
+	obj, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.FederationResource}).Get(ctx, fed, v1.GetOptions{})
+	if err != nil {
+		return PolicyAll
+	}
+	if policy, _, _ := unstructured.NestedString(obj.Object, "spec", "policy"); policy != "" {
+		return policy
+	}
+	return PolicyAll
+}
+
+// VoteStatus is the vote of an organization recorded in proposal status
+type VoteStatus struct {
+	Organization string
+	Namespace    string
+	Name         string
+	Phase        string
+	// Decision is nil if the organization has not voted yet
+	Decision    *bool
+	Description string
+	VoteTime    string
+}
+
+// Votes returns the votes in status of proposal, sorted by organization
+func Votes(proposal *unstructured.Unstructured) []VoteStatus {
+	raw, _, _ := unstructured.NestedSlice(proposal.Object, "status", "votes")
+	votes := make([]VoteStatus, 0, len(raw))
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v := VoteStatus{}
+		v.Organization, _, _ = unstructured.NestedString(m, "organizationName")
+		v.Namespace, _, _ = unstructured.NestedString(m, "namespace")
+		v.Name, _, _ = unstructured.NestedString(m, "name")
+		v.Phase, _, _ = unstructured.NestedString(m, "phase")
+		v.Description, _, _ = unstructured.NestedString(m, "description")
+		v.VoteTime, _, _ = unstructured.NestedString(m, "voteTime")
+		if decision, found, _ := unstructured.NestedBool(m, "decision"); found {
+			v.Decision = &decision
+		}
+		votes = append(votes, v)
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		return votes[i].Organization < votes[j].Organization
+	})
+	return votes
+}
+
+// PendingOrganizations returns the organizations which have not voted on proposal yet
+func PendingOrganizations(proposal *unstructured.Unstructured) []string {
+	pending := make([]string, 0)
+	for _, v := range Votes(proposal) {
+		if v.Decision == nil {
+			pending = append(pending, v.Organization)
+		}
+	}
+	return pending
+}
+
+// WaitForVotes waits until the votes of proposal are created by the operator and returns the proposal
+func WaitForVotes(ctx context.Context, cli dynamic.Interface, name string, timeout time.Duration) (*unstructured.Unstructured, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var proposal *unstructured.Unstructured
+	err := wait.PollUntilContextCancel(ctx, common.WaitInterval, true, func(ctx context.Context) (bool, error) {
+		obj, err := cli.Resource(proposalGVR).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		proposal = obj
+		return len(Votes(obj)) > 0, nil
+	})
+	return proposal, err
+}
+
+// votesTimeout is the time to wait for the operator to create votes of a new proposal
+var votesTimeout = 10 * time.Second
+
+// AddFlags adds the flags shared by the commands which create proposals
+func AddFlags(cmd *cobra.Command, options *Options) {
+	cmd.Flags().StringVar(&options.Initiator, "initiator", "", "organization to initiate the proposal, default to the only organization administered by the current user")
+	cmd.Flags().StringVar(&options.Name, "proposal", "", "name of the proposal, generated if empty")
+	cmd.Flags().DurationVar(&options.Duration, "vote-duration", 24*time.Hour, "the length of time for organizations to vote")
+}
+
+// CreateAndReport creates proposal p and reports the organizations which need to vote
+func CreateAndReport(ctx context.Context, cli dynamic.Interface, out io.Writer, p *unstructured.Unstructured) error {
+	if _, err := CreateProposal(ctx, cli, p); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "proposal/%s created\n", p.GetName())
+
+	created, err := WaitForVotes(ctx, cli, p.GetName(), votesTimeout)
+	if err != nil {
+		fmt.Fprintf(out, "votes are not created yet, check them later with `bc-cli get proposal %s`\n", p.GetName())
+		return nil
+	}
+	pending := PendingOrganizations(created)
+	if len(pending) == 0 {
+		fmt.Fprintln(out, "all organizations have voted")
+		return nil
+	}
+	fmt.Fprintf(out, "waiting for votes from: %s\n", strings.Join(pending, ", "))
+	return nil
+}
+
+// CreateWithProposal creates obj and then proposal p for it. The operator only acts on obj once p passes,
+// so obj is deleted if p can not be created, otherwise it would be left with no way to approve it.
+func CreateWithProposal(ctx context.Context, cli dynamic.Interface, out io.Writer, gvr schema.GroupVersionResource, obj *unstructured.Unstructured, p *unstructured.Unstructured) error {
+	ri := cli.Resource(gvr).Namespace(obj.GetNamespace())
+	if _, err := ri.Create(ctx, obj, v1.CreateOptions{}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s/%s created\n", strings.ToLower(obj.GetKind()), obj.GetName())
+
+	if err := CreateAndReport(ctx, cli, out, p); err != nil {
+		if delErr := ri.Delete(ctx, obj.GetName(), v1.DeleteOptions{}); delErr != nil {
+			return fmt.Errorf("create proposal: %w, and %s/%s is left without a proposal: %s", err, strings.ToLower(obj.GetKind()), obj.GetName(), delErr)
+		}
+		fmt.Fprintf(out, "%s/%s deleted\n", strings.ToLower(obj.GetKind()), obj.GetName())
+		return fmt.Errorf("create proposal: %w", err)
+	}
+	return nil
+}
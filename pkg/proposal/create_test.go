@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proposal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestNewProposal(t *testing.T) {
+	p := NewProposal(Options{Initiator: "org1", Policy: PolicyAll, Duration: time.Hour}, SourceDissolveFederation, map[string]interface{}{
+		"federation": "fed1",
+	})
+	assert.True(t, strings.HasPrefix(p.GetName(), "dissolve-federation-"), p.GetName())
+	fed, _, _ := unstructured.NestedString(p.Object, "spec", SourceDissolveFederation, "federation")
+	assert.Equal(t, "fed1", fed)
+
+	startAt, _, _ := unstructured.NestedString(p.Object, "spec", "startAt")
+	endAt, _, _ := unstructured.NestedString(p.Object, "spec", "endAt")
+	start, err := time.Parse(time.RFC3339, startAt)
+	assert.NoError(t, err)
+	end, err := time.Parse(time.RFC3339, endAt)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, end.Sub(start))
+
+	// generated names are valid object names and do not collide
+	names := map[string]bool{}
+	for _, source := range Sources {
+		name := GenerateName(source)
+		assert.Empty(t, validation.IsDNS1123Subdomain(name), name)
+		assert.False(t, names[name], name)
+		names[name] = true
+		assert.NotEqual(t, name, GenerateName(source))
+	}
+
+	assert.NoError(t, ValidatePolicy(PolicyOneVoteVeto))
+	assert.Error(t, ValidatePolicy("Any"))
+}
+
+func TestPendingOrganizations(t *testing.T) {
+	p := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	assert.NoError(t, unstructured.SetNestedSlice(p.Object, []interface{}{
+		map[string]interface{}{"organizationName": "org3", "phase": VotePhaseCreated},
+		map[string]interface{}{"organizationName": "org1", "phase": VotePhaseVoted, "decision": true},
+		map[string]interface{}{"organizationName": "org2", "phase": VotePhaseCreated},
+	}, "status", "votes"))
+
+	votes := Votes(p)
+	assert.Equal(t, "org1", votes[0].Organization)
+	assert.True(t, *votes[0].Decision)
+	assert.Equal(t, []string{"org2", "org3"}, PendingOrganizations(p))
+}
+
+func TestPrintSummary(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	p := NewProposal(Options{Name: "pro1", Policy: PolicyMajority, Duration: time.Hour}, SourceCreateFederation, map[string]interface{}{})
+	assert.NoError(t, unstructured.SetNestedField(p.Object, now.Add(2*time.Hour).Format(time.RFC3339), "spec", "endAt"))
+	assert.NoError(t, unstructured.SetNestedSlice(p.Object, []interface{}{
+		map[string]interface{}{"organizationName": "org1", "decision": true},
+		map[string]interface{}{"organizationName": "org2", "decision": false},
+		map[string]interface{}{"organizationName": "org3"},
+	}, "status", "votes"))
+
+	out := new(bytes.Buffer)
+	PrintSummary(out, p, now)
+	assert.Contains(t, out.String(), "proposal/pro1: Pending")
+	assert.Contains(t, out.String(), "policy:   Majority")
+	assert.Contains(t, out.String(), "(120m left)")
+	assert.Contains(t, out.String(), "approved: org1\n")
+	assert.Contains(t, out.String(), "rejected: org2\n")
+	assert.Contains(t, out.String(), "pending:  org3\n")
+}
+
+func TestCreateWithProposal(t *testing.T) {
+	gvr := schema.GroupVersionResource{Group: "ibp.com", Version: "v1beta1", Resource: "federations"}
+	fed := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "ibp.com/v1beta1",
+		"kind":       "Federation",
+		"metadata":   map[string]interface{}{"name": "fed1"},
+	}}
+	cli := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	cli.PrependReactor("create", "proposals", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("denied")
+	})
+	p := NewProposal(Options{Initiator: "org1", Policy: PolicyAll, Duration: time.Hour}, SourceCreateFederation, map[string]interface{}{
+		"federation": "fed1",
+	})
+
+	// the federation is deleted when its proposal can not be created
+	out := new(bytes.Buffer)
+	err := CreateWithProposal(context.TODO(), cli, out, gvr, fed, p)
+	assert.ErrorContains(t, err, "denied")
+	assert.Equal(t, "federation/fed1 created\nfederation/fed1 deleted\n", out.String())
+	_, err = cli.Resource(gvr).Get(context.TODO(), "fed1", v1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), err)
+}
@@ -18,8 +18,8 @@ package proposal
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -28,17 +28,51 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/kubectl/pkg/cmd/get"
 
 	"github.com/bestchains/bc-cli/pkg/common"
 	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/printer"
+	"github.com/bestchains/bc-cli/pkg/resource"
 	"github.com/bestchains/bc-cli/pkg/utils"
 )
 
+// Kind is the kind of proposals, which are visible to the administrators of their voters
+var Kind = resource.Kind{
+	Kind: "Proposal",
+	Name: "proposal",
+	GVR:  proposalGVR,
+	Visible: func(ctx context.Context, cli dynamic.Interface) (func(*unstructured.Unstructured) bool, error) {
+		adminOrgs, err := org.ListAdminOrganizations(cli, viper.GetString("auth.username"))
+		if err != nil {
+			return nil, err
+		}
+		return VisibleTo(adminOrgs), nil
+	},
+}
+
+// VisibleTo returns a filter keeping proposals which any of organizations votes on
+func VisibleTo(organizations []string) func(*unstructured.Unstructured) bool {
+	return func(proposal *unstructured.Unstructured) bool {
+		for _, v := range Votes(proposal) {
+			if utils.ContainsString(organizations, v.Organization) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 func NewProposalGetCmd(option common.Options) *cobra.Command {
+	var (
+		pendingForMe bool
+		watchFlags   common.WatchFlags
+	)
 	defaultPrintFlag := get.NewGetPrintFlags()
 	cmd := &cobra.Command{
-		Use:   "proposal [NAME]",
+		Use:   "proposal [NAME] [--pending-for-me] [-o wide]",
 		Short: "Get a list of proposal",
 		Run: func(cmd *cobra.Command, args []string) {
 			cli, err := common.GetDynamicClient()
@@ -47,24 +81,17 @@ func NewProposalGetCmd(option common.Options) *cobra.Command {
 				return
 			}
 
-			list := corev1.List{
-				TypeMeta: v1.TypeMeta{
-					Kind:       "List",
-					APIVersion: "v1",
-				},
-				ListMeta: v1.ListMeta{},
-			}
-			var obj runtime.Object
-			if len(args) == 0 {
-				username := viper.GetString("auth.username")
-				organizations, err := org.ListOrganizations(cli, fmt.Sprintf("bestchains.organization.admin=%s", username), "")
+			var adminOrgs []string
+			if len(args) == 0 || pendingForMe {
+				adminOrgs, err = org.ListAdminOrganizations(cli, viper.GetString("auth.username"))
 				if err != nil {
 					fmt.Fprintln(option.ErrOut, err)
 					return
 				}
-				var proposalNames []string
-				for _, org := range organizations.Items {
-					namespace := org.GetName()
+			}
+			proposalNames := utils.RemoveDuplicateForStringSlice(args)
+			if len(args) == 0 {
+				for _, namespace := range adminOrgs {
 					votes, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Vote}).Namespace(namespace).List(context.TODO(), v1.ListOptions{})
 					if err != nil {
 						fmt.Fprintln(option.ErrOut, err)
@@ -75,37 +102,86 @@ func NewProposalGetCmd(option common.Options) *cobra.Command {
 						proposalNames = append(proposalNames, proposalName)
 					}
 				}
-				for _, proposalName := range utils.RemoveDuplicateForStringSlice(proposalNames) {
-					proposal, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Proposal}).Get(context.TODO(), proposalName, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						continue
+				proposalNames = utils.RemoveDuplicateForStringSlice(proposalNames)
+			}
+
+			proposals := make([]*unstructured.Unstructured, 0, len(proposalNames))
+			for _, proposalName := range proposalNames {
+				proposal, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Proposal}).Get(context.TODO(), proposalName, v1.GetOptions{})
+				if err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					continue
+				}
+				if pendingForMe && !PendingFor(proposal, adminOrgs) {
+					continue
+				}
+				proposals = append(proposals, proposal)
+			}
+
+			names := make([]string, 0, len(proposals))
+			for _, proposal := range proposals {
+				names = append(names, proposal.GetName())
+			}
+			// keep the proposals which would be listed above
+			filter := common.HasName(args)
+			if len(args) == 0 {
+				filter = VisibleTo(adminOrgs)
+			}
+			if pendingForMe {
+				visible := filter
+				filter = func(proposal *unstructured.Unstructured) bool {
+					return visible(proposal) && PendingFor(proposal, adminOrgs)
+				}
+			}
+
+			// the summary table is printed unless another output format is asked
+			if format := *defaultPrintFlag.OutputFormat; format == "" || format == "wide" {
+				headers := Headers(format == "wide")
+				if !watchFlags.WatchOnly {
+					now := time.Now()
+					rows := make([]printer.Printer, 0, len(proposals))
+					for _, proposal := range proposals {
+						rows = append(rows, Summary{Proposal: proposal, Now: now})
 					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: proposal})
+					printer.Print(option.Out, headers, rows)
 				}
-			} else {
-				for _, arg := range utils.RemoveDuplicateForStringSlice(args) {
-					proposal, err := cli.Resource(schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Proposal}).Get(context.TODO(), arg, v1.GetOptions{})
-					if err != nil {
-						fmt.Fprintln(option.ErrOut, err)
-						continue
+				if !watchFlags.Enabled() {
+					return
+				}
+				headerPrinted := !watchFlags.WatchOnly
+				handle := func(_ watch.EventType, proposal *unstructured.Unstructured) error {
+					rows := []printer.Printer{Summary{Proposal: proposal, Now: time.Now()}}
+					if !headerPrinted {
+						headerPrinted = true
+						printer.Print(option.Out, headers, rows)
+						return nil
 					}
-					list.Items = append(list.Items, runtime.RawExtension{Object: proposal})
+					printer.PrintRows(option.Out, headers, rows)
+					return nil
+				}
+				if err := common.Watch(cmd.Context(), cli.Resource(proposalGVR), v1.ListOptions{}, common.Seen(names, filter), handle); err != nil {
+					fmt.Fprintln(option.ErrOut, err)
 				}
+				return
 			}
 
+			list := corev1.List{
+				TypeMeta: v1.TypeMeta{
+					Kind:       "List",
+					APIVersion: "v1",
+				},
+				ListMeta: v1.ListMeta{},
+			}
+			for _, proposal := range proposals {
+				list.Items = append(list.Items, runtime.RawExtension{Object: proposal})
+			}
+			var obj runtime.Object
 			if len(list.Items) != 1 {
-				listData, err := json.Marshal(list)
+				obj, err = common.ListToObj(list)
 				if err != nil {
 					fmt.Fprintln(option.ErrOut, err)
 					return
 				}
-				converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, listData)
-				if err != nil {
-					fmt.Fprintln(option.ErrOut, err)
-					return
-				}
-				obj = converted
 			} else {
 				obj = list.Items[0].Object
 			}
@@ -115,10 +191,20 @@ func NewProposalGetCmd(option common.Options) *cobra.Command {
 				fmt.Fprintln(option.ErrOut, err)
 				return
 			}
-			_ = p.PrintObj(obj, option.Out)
+			if !watchFlags.WatchOnly {
+				_ = p.PrintObj(obj, option.Out)
+			}
+			if !watchFlags.Enabled() {
+				return
+			}
+			if err := common.Watch(cmd.Context(), cli.Resource(proposalGVR), v1.ListOptions{}, common.Seen(names, filter), common.NewWatchPrinter(p, option.Out).Handler()); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+			}
 		},
 	}
 	defaultPrintFlag.AddFlags(cmd)
+	watchFlags.AddFlags(cmd)
+	cmd.Flags().BoolVar(&pendingForMe, "pending-for-me", false, "only list proposals which wait for votes of organizations administered by the current user")
 
 	return cmd
 }
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proposal
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+
+	"github.com/bestchains/bc-cli/pkg/printer"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+var (
+	headers     = []string{"name", "type", "initiator", "phase", "approved", "required", "pending", "remaining"}
+	wideHeaders = append(append([]string{}, headers...), "policy", "rejected", "deadline")
+)
+
+// Headers returns the table headers of proposals, wide adds policy, rejected organizations and deadline
+func Headers(wide bool) []string {
+	if wide {
+		return wideHeaders
+	}
+	return headers
+}
+
+// commonSpecFields are the fields in spec of all kinds of proposals,
+// the other field is the source which decides the type of the proposal.
+var commonSpecFields = map[string]bool{
+	"policy":     true,
+	"initiator":  true,
+	"startAt":    true,
+	"endAt":      true,
+	"deprecated": true,
+}
+
+// Type returns the source of proposal, such as createFederation. Sources unknown to bc-cli are
+// looked up in alphabetical order after the known ones.
+func Type(proposal *unstructured.Unstructured) string {
+	spec, _, _ := unstructured.NestedMap(proposal.Object, "spec")
+	for _, source := range Sources {
+		if spec[source] != nil {
+			return source
+		}
+	}
+	keys := make([]string, 0, len(spec))
+	for k, v := range spec {
+		if !commonSpecFields[k] && v != nil {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return "<unknown>"
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
+
+// Phase returns status.phase of proposal, Pending if the operator has not handled it yet
+func Phase(proposal *unstructured.Unstructured) string {
+	phase, _, _ := unstructured.NestedString(proposal.Object, "status", "phase")
+	if phase == "" {
+		return PhasePending
+	}
+	return phase
+}
+
+// RequiredApprovals returns the number of approvals to pass a proposal of policy with voters organizations
+func RequiredApprovals(policy string, voters int) int {
+	if policy == PolicyMajority {
+		return voters/2 + 1
+	}
+	// All and OneVoteVeto need every organization to approve
+	return voters
+}
+
+// Failed reports whether proposal has been rejected by so many organizations that it can not pass
+func Failed(proposal *unstructured.Unstructured) bool {
+	tally := NewTally(proposal)
+	voters := len(tally.Approved) + len(tally.Rejected) + len(tally.Pending)
+	policy, _, _ := unstructured.NestedString(proposal.Object, "spec", "policy")
+	return voters > 0 && voters-len(tally.Rejected) < RequiredApprovals(policy, voters)
+}
+
+// Summary prints a proposal as a table row
+type Summary struct {
+	Proposal *unstructured.Unstructured
+	// Now is the time to compute the remaining time to vote
+	Now time.Time
+}
+
+var _ printer.Printer = Summary{}
+
+func (s Summary) GetByHeader(header string) string {
+	tally := NewTally(s.Proposal)
+	voters := len(tally.Approved) + len(tally.Rejected) + len(tally.Pending)
+	policy, _, _ := unstructured.NestedString(s.Proposal.Object, "spec", "policy")
+	switch header {
+	case "name":
+		return s.Proposal.GetName()
+	case "type":
+		return Type(s.Proposal)
+	case "initiator":
+		return orNone(utils.GetNestedString(s.Proposal.Object, "spec", "initiator"))
+	case "phase":
+		return Phase(s.Proposal)
+	case "approved":
+		return fmt.Sprintf("%d/%d", len(tally.Approved), voters)
+	case "required":
+		return fmt.Sprintf("%d (%s)", RequiredApprovals(policy, voters), policy)
+	case "pending":
+		return joinOrNone(tally.Pending)
+	case "rejected":
+		return joinOrNone(tally.Rejected)
+	case "policy":
+		return orNone(policy)
+	case "remaining":
+		deadline := Deadline(s.Proposal)
+		switch {
+		case Phase(s.Proposal) == PhaseFinished:
+			return "-"
+		case deadline.IsZero():
+			return "<none>"
+		case !deadline.After(s.Now):
+			return "expired"
+		}
+		return duration.HumanDuration(deadline.Sub(s.Now))
+	case "deadline":
+		if deadline := Deadline(s.Proposal); !deadline.IsZero() {
+			return deadline.Local().Format(time.RFC3339)
+		}
+		return "<none>"
+	}
+	return "<none>"
+}
+
+// PendingFor reports whether any of organizations has not voted on proposal
+func PendingFor(proposal *unstructured.Unstructured, organizations []string) bool {
+	for _, pending := range PendingOrganizations(proposal) {
+		if utils.ContainsString(organizations, pending) {
+			return true
+		}
+	}
+	return false
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proposal
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/bestchains/bc-cli/pkg/printer"
+)
+
+func TestSummary(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	p := NewProposal(Options{Name: "pro1", Initiator: "org1", Policy: PolicyMajority}, SourceAddMember, map[string]interface{}{
+		"federation": "fed1",
+		"members":    []interface{}{"org4"},
+	})
+	assert.NoError(t, unstructured.SetNestedField(p.Object, now.Add(90*time.Minute).Format(time.RFC3339), "spec", "endAt"))
+	assert.NoError(t, unstructured.SetNestedField(p.Object, PhaseVoting, "status", "phase"))
+	assert.NoError(t, unstructured.SetNestedSlice(p.Object, []interface{}{
+		map[string]interface{}{"organizationName": "org1", "decision": true},
+		map[string]interface{}{"organizationName": "org2", "decision": false},
+		map[string]interface{}{"organizationName": "org3"},
+		map[string]interface{}{"organizationName": "org4"},
+	}, "status", "votes"))
+
+	s := Summary{Proposal: p, Now: now}
+	assert.Equal(t, SourceAddMember, s.GetByHeader("type"))
+	assert.Equal(t, "org1", s.GetByHeader("initiator"))
+	assert.Equal(t, PhaseVoting, s.GetByHeader("phase"))
+	assert.Equal(t, "1/4", s.GetByHeader("approved"))
+	assert.Equal(t, "3 (Majority)", s.GetByHeader("required"))
+	assert.Equal(t, "org3, org4", s.GetByHeader("pending"))
+	assert.Equal(t, "org2", s.GetByHeader("rejected"))
+	assert.Equal(t, "90m", s.GetByHeader("remaining"))
+	assert.Equal(t, "expired", Summary{Proposal: p, Now: now.Add(2 * time.Hour)}.GetByHeader("remaining"))
+
+	assert.True(t, PendingFor(p, []string{"org5", "org4"}))
+	assert.False(t, PendingFor(p, []string{"org1", "org2"}))
+
+	// one rejection still allows a majority, but not all organizations to approve
+	assert.False(t, Failed(p))
+	all := p.DeepCopy()
+	assert.NoError(t, unstructured.SetNestedField(all.Object, PolicyAll, "spec", "policy"))
+	assert.True(t, Failed(all))
+
+	out := new(bytes.Buffer)
+	printer.Print(out, Headers(true), []printer.Printer{s})
+	assert.Contains(t, out.String(), "DEADLINE")
+	assert.Contains(t, out.String(), "pro1")
+}
+
+func TestRequiredApprovals(t *testing.T) {
+	assert.Equal(t, 3, RequiredApprovals(PolicyAll, 3))
+	assert.Equal(t, 3, RequiredApprovals(PolicyOneVoteVeto, 3))
+	assert.Equal(t, 2, RequiredApprovals(PolicyMajority, 3))
+	assert.Equal(t, 3, RequiredApprovals(PolicyMajority, 4))
+}
+
+func TestType(t *testing.T) {
+	p := NewProposal(Options{Name: "pro1", Policy: PolicyAll}, SourceUpgradeChaincode, map[string]interface{}{"chaincode": "cc1"})
+	assert.NoError(t, unstructured.SetNestedField(p.Object, map[string]interface{}{"channel": "ch1"}, "spec", "archiveChannel"))
+	assert.NoError(t, unstructured.SetNestedField(p.Object, map[string]interface{}{"channel": "ch1"}, "spec", "unarchiveChannel"))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, SourceUpgradeChaincode, Type(p))
+	}
+	unstructured.RemoveNestedField(p.Object, "spec", SourceUpgradeChaincode)
+	assert.Equal(t, "archiveChannel", Type(p))
+
+	summary := Summary{Proposal: p, Now: time.Now()}
+	assert.Equal(t, PhasePending, summary.GetByHeader("phase"))
+	assert.NoError(t, unstructured.SetNestedField(p.Object, PhaseFinished, "status", "phase"))
+	assert.Equal(t, "-", summary.GetByHeader("remaining"))
+}
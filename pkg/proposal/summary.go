@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proposal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Tally counts the votes of a proposal by organization
+type Tally struct {
+	Approved []string
+	Rejected []string
+	Pending  []string
+}
+
+// NewTally returns the tally of the votes in status of proposal
+func NewTally(proposal *unstructured.Unstructured) Tally {
+	tally := Tally{Approved: []string{}, Rejected: []string{}, Pending: []string{}}
+	for _, v := range Votes(proposal) {
+		switch {
+		case v.Decision == nil:
+			tally.Pending = append(tally.Pending, v.Organization)
+		case *v.Decision:
+			tally.Approved = append(tally.Approved, v.Organization)
+		default:
+			tally.Rejected = append(tally.Rejected, v.Organization)
+		}
+	}
+	return tally
+}
+
+// Deadline returns spec.endAt of proposal, zero time if not set
+func Deadline(proposal *unstructured.Unstructured) time.Time {
+	endAt, _, _ := unstructured.NestedString(proposal.Object, "spec", "endAt")
+	t, _ := time.Parse(time.RFC3339, endAt)
+	return t
+}
+
+// PrintSummary prints the phase, policy, deadline and tally of proposal
+func PrintSummary(out io.Writer, proposal *unstructured.Unstructured, now time.Time) {
+	phase := Phase(proposal)
+	policy, _, _ := unstructured.NestedString(proposal.Object, "spec", "policy")
+	fmt.Fprintf(out, "proposal/%s: %s\n", proposal.GetName(), phase)
+	fmt.Fprintf(out, "  policy:   %s\n", policy)
+	if deadline := Deadline(proposal); !deadline.IsZero() {
+		left := "expired"
+		if deadline.After(now) {
+			left = duration.HumanDuration(deadline.Sub(now)) + " left"
+		}
+		fmt.Fprintf(out, "  deadline: %s (%s)\n", deadline.Local().Format(time.RFC3339), left)
+	}
+	tally := NewTally(proposal)
+	fmt.Fprintf(out, "  approved: %s\n", joinOrNone(tally.Approved))
+	fmt.Fprintf(out, "  rejected: %s\n", joinOrNone(tally.Rejected))
+	fmt.Fprintf(out, "  pending:  %s\n", joinOrNone(tally.Pending))
+}
+
+func joinOrNone(s []string) string {
+	if len(s) == 0 {
+		return "<none>"
+	}
+	return strings.Join(s, ", ")
+}
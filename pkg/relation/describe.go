@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package relation
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+var eventGVR = schema.GroupVersionResource{Version: common.CoreVersion, Resource: "events"}
+
+// MaxEvents is the max number of recent events printed by describe
+var MaxEvents = 10
+
+// Event is a kubernetes event of a resource
+type Event struct {
+	Type    string
+	Reason  string
+	From    string
+	Message string
+	Count   int64
+	Last    time.Time
+}
+
+// Events returns the recent events of obj, the latest one is the last
+func Events(ctx context.Context, cli dynamic.Interface, obj *unstructured.Unstructured) ([]Event, error) {
+	list, err := cli.Resource(eventGVR).List(ctx, v1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=%s,involvedObject.name=%s", obj.GetKind(), obj.GetName()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(list.Items))
+	for _, item := range list.Items {
+		// the selector is not supported by every server, so filter again
+		if utils.GetNestedString(item.Object, "involvedObject", "kind") != obj.GetKind() ||
+			utils.GetNestedString(item.Object, "involvedObject", "name") != obj.GetName() {
+			continue
+		}
+		e := Event{
+			Type:    utils.GetNestedString(item.Object, "type"),
+			Reason:  utils.GetNestedString(item.Object, "reason"),
+			From:    utils.GetNestedString(item.Object, "source", "component"),
+			Message: utils.GetNestedString(item.Object, "message"),
+		}
+		e.Count, _, _ = unstructured.NestedInt64(item.Object, "count")
+		for _, field := range []string{"lastTimestamp", "eventTime", "firstTimestamp"} {
+			if t, err := time.Parse(time.RFC3339, utils.GetNestedString(item.Object, field)); err == nil {
+				e.Last = t
+				break
+			}
+		}
+		if e.Last.IsZero() {
+			e.Last = item.GetCreationTimestamp().Time
+		}
+		events = append(events, e)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Last.Before(events[j].Last)
+	})
+	if len(events) > MaxEvents {
+		events = events[len(events)-MaxEvents:]
+	}
+	return events, nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// Describe prints the details of obj written by details, followed by its related resources and recent events.
+// Failures to find related resources or events are printed instead of failing the description.
+func Describe(ctx context.Context, cli dynamic.Interface, out io.Writer, obj *unstructured.Unstructured, details func(w describe.PrefixWriter)) {
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	w := describe.NewPrefixWriter(tw)
+	details(w)
+
+	w.Write(describe.LEVEL_0, "Related Resources:\n")
+	g := NewGraph(cli)
+	ref := Ref{Kind: obj.GetKind(), Name: obj.GetName()}
+	g.objects[ref] = obj
+	references, err := g.References(ctx, ref)
+	if err != nil {
+		klog.V(2).Infof("failed to find the references of %s: %s", ref, err)
+		w.Write(describe.LEVEL_1, "<error: %s>\n", err)
+	}
+	tree, err := g.Tree(ctx, ref, -1)
+	if err != nil {
+		klog.V(2).Infof("failed to find the children of %s: %s", ref, err)
+		w.Write(describe.LEVEL_1, "<error: %s>\n", err)
+		tree = &Node{}
+	}
+	if len(references) == 0 && len(tree.Children) == 0 {
+		w.Write(describe.LEVEL_1, "<none>\n")
+	}
+	for _, r := range references {
+		w.Write(describe.LEVEL_1, "%s\n", r)
+	}
+	var walk func(nodes []*Node, level int)
+	walk = func(nodes []*Node, level int) {
+		for _, n := range nodes {
+			if n.Missing {
+				w.Write(level, "%s (not found)\n", n.Ref)
+				continue
+			}
+			w.Write(level, "%s\n", n.Ref)
+			walk(n.Children, level+1)
+		}
+	}
+	walk(tree.Children, describe.LEVEL_1)
+
+	events, err := Events(ctx, cli, obj)
+	switch {
+	case err != nil:
+		w.Write(describe.LEVEL_0, "Events:\t<error: %s>\n", err)
+	case len(events) == 0:
+		w.Write(describe.LEVEL_0, "Events:\t<none>\n")
+	default:
+		w.Write(describe.LEVEL_0, "Events:\n")
+		w.Write(describe.LEVEL_1, "TYPE\tREASON\tAGE\tFROM\tMESSAGE\n")
+		now := time.Now()
+		for _, e := range events {
+			age := "<unknown>"
+			if !e.Last.IsZero() {
+				age = duration.HumanDuration(now.Sub(e.Last))
+				if e.Count > 1 {
+					age = fmt.Sprintf("%s (x%d)", age, e.Count)
+				}
+			}
+			w.Write(describe.LEVEL_1, "%s\t%s\t%s\t%s\t%s\n", orNone(e.Type), orNone(e.Reason), age, orNone(e.From), e.Message)
+		}
+	}
+	_ = tw.Flush()
+}
+
+// WriteCommon writes the status, description and creation time shared by all kinds
+func WriteCommon(w describe.PrefixWriter, obj *unstructured.Unstructured) {
+	w.Write(describe.LEVEL_0, "Status:\t%s\n", orNone(Status(obj)))
+	if description := utils.GetNestedString(obj.Object, "spec", "description"); description != "" {
+		w.Write(describe.LEVEL_0, "Description:\t%s\n", description)
+	}
+	w.Write(describe.LEVEL_0, "Created:\t%s\n", obj.GetCreationTimestamp().Format(time.RFC3339))
+}
+
+// NewDescribeCmd returns the command to describe resources of kind, details writes the fields specific to the kind
+func NewDescribeCmd(option common.Options, kind string, cmd *cobra.Command, details func(w describe.PrefixWriter, obj *unstructured.Unstructured)) *cobra.Command {
+	cmd.Args = cobra.MinimumNArgs(1)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		cli, err := common.GetDynamicClient()
+		if err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		var lastErr error
+		printed := false
+		for _, name := range utils.RemoveDuplicateForStringSlice(args) {
+			obj, err := cli.Resource(GVR(kind)).Get(cmd.Context(), name, v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				lastErr = err
+				continue
+			}
+			if printed {
+				fmt.Fprintln(option.Out)
+			}
+			printed = true
+			Describe(cmd.Context(), cli, option.Out, obj, func(w describe.PrefixWriter) {
+				details(w, obj)
+			})
+		}
+		return lastErr
+	}
+	return cmd
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package relation finds how bestchains resources connect to each other, such as the federation
+// and channels of a network, the chaincodes and endorsepolicies of a channel.
+package relation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// Kinds of bestchains resources
+const (
+	KindOrganization   = "Organization"
+	KindFederation     = "Federation"
+	KindNetwork        = "Network"
+	KindChannel        = "Channel"
+	KindChaincode      = "Chaincode"
+	KindEndorsePolicy  = "EndorsePolicy"
+	KindChaincodeBuild = "ChaincodeBuild"
+)
+
+var resources = map[string]string{
+	KindOrganization:   common.OrganizationResource,
+	KindFederation:     common.FederationResource,
+	KindNetwork:        common.NetworkResource,
+	KindChannel:        common.Channel,
+	KindChaincode:      common.Chaincode,
+	KindEndorsePolicy:  common.EndorsePolicy,
+	KindChaincodeBuild: common.ChaincodeBuild,
+}
+
+// GVR returns the resource of kind
+func GVR(kind string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: resources[kind]}
+}
+
+// Ref refers to a resource by kind and name
+type Ref struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+func (r Ref) String() string {
+	return strings.ToLower(r.Kind) + "/" + r.Name
+}
+
+// Node is a resource with the resources it owns, such as the channels of a network
+type Node struct {
+	Ref
+	// Status is status.type or status.phase of the resource
+	Status string `json:"status,omitempty"`
+	// Missing is true if the resource is referred to but can not be found
+	Missing  bool    `json:"missing,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// Graph finds the related resources, resources are cached so that each one is fetched once
+type Graph struct {
+	cli     dynamic.Interface
+	objects map[Ref]*unstructured.Unstructured
+	lists   map[string][]unstructured.Unstructured
+}
+
+// NewGraph returns a graph of the resources in cli
+func NewGraph(cli dynamic.Interface) *Graph {
+	return &Graph{
+		cli:     cli,
+		objects: map[Ref]*unstructured.Unstructured{},
+		lists:   map[string][]unstructured.Unstructured{},
+	}
+}
+
+// Get returns the resource ref refers to
+func (g *Graph) Get(ctx context.Context, ref Ref) (*unstructured.Unstructured, error) {
+	if obj, ok := g.objects[ref]; ok {
+		return obj, nil
+	}
+	if _, ok := resources[ref.Kind]; !ok {
+		return nil, fmt.Errorf("unknown kind %s", ref.Kind)
+	}
+	obj, err := g.cli.Resource(GVR(ref.Kind)).Get(ctx, ref.Name, v1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	g.objects[ref] = obj
+	return obj, nil
+}
+
+// list returns all resources of kind
+func (g *Graph) list(ctx context.Context, kind string) ([]unstructured.Unstructured, error) {
+	if items, ok := g.lists[kind]; ok {
+		return items, nil
+	}
+	list, err := g.cli.Resource(GVR(kind)).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	g.lists[kind] = list.Items
+	return list.Items, nil
+}
+
+// Status returns status.type of obj, or status.phase for kinds such as chaincodes which report phases
+func Status(obj *unstructured.Unstructured) string {
+	if t := common.StatusType(obj); t != "" {
+		return t
+	}
+	return utils.GetNestedString(obj.Object, "status", "phase")
+}
+
+// Members returns the organizations in spec.members of federations, networks and channels
+func Members(obj *unstructured.Unstructured) []string {
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "spec", "members")
+	members := make([]string, 0, len(raw))
+	for _, item := range raw {
+		if m, ok := item.(map[string]interface{}); ok {
+			if name := utils.GetNestedString(m, "name"); name != "" {
+				members = append(members, name)
+			}
+		}
+	}
+	return members
+}
+
+// FormatMembers returns the members of obj separated by commas, the initiator is marked
+func FormatMembers(obj *unstructured.Unstructured) string {
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "spec", "members")
+	members := make([]string, 0, len(raw))
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := utils.GetNestedString(m, "name")
+		if initiator, _, _ := unstructured.NestedBool(m, "initiator"); initiator {
+			name += " (initiator)"
+		}
+		members = append(members, name)
+	}
+	if len(members) == 0 {
+		return "<none>"
+	}
+	return strings.Join(members, ", ")
+}
+
+// Initiator returns the member of obj marked as the initiator, empty if there is none
+func Initiator(obj *unstructured.Unstructured) string {
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "spec", "members")
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if initiator, _, _ := unstructured.NestedBool(m, "initiator"); initiator {
+			return utils.GetNestedString(m, "name")
+		}
+	}
+	return ""
+}
+
+func refs(kind string, names ...string) []Ref {
+	result := make([]Ref, 0, len(names))
+	for _, name := range names {
+		if name != "" {
+			result = append(result, Ref{Kind: kind, Name: name})
+		}
+	}
+	return result
+}
+
+// Children returns the resources owned by ref: the federations of an organization, the networks of a federation,
+// the channels of a network, and the chaincodes and endorsepolicies of a channel.
+func (g *Graph) Children(ctx context.Context, ref Ref) ([]Ref, error) {
+	obj, err := g.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	switch ref.Kind {
+	case KindOrganization:
+		feds, _, _ := unstructured.NestedStringSlice(obj.Object, "status", "federations")
+		return refs(KindFederation, feds...), nil
+	case KindFederation:
+		networks, _, _ := unstructured.NestedStringSlice(obj.Object, "status", "networks")
+		return refs(KindNetwork, networks...), nil
+	case KindNetwork:
+		channels, _, _ := unstructured.NestedStringSlice(obj.Object, "status", "channels")
+		return refs(KindChannel, channels...), nil
+	case KindChannel:
+		var chaincodes, eps []string
+		items, err := g.list(ctx, KindChaincode)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if utils.GetNestedString(item.Object, "spec", "channel") == ref.Name {
+				chaincodes = append(chaincodes, item.GetName())
+			}
+		}
+		if items, err = g.list(ctx, KindEndorsePolicy); err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if utils.GetNestedString(item.Object, "spec", "channel") == ref.Name {
+				eps = append(eps, item.GetName())
+			}
+		}
+		sort.Strings(chaincodes)
+		sort.Strings(eps)
+		return append(refs(KindChaincode, chaincodes...), refs(KindEndorsePolicy, eps...)...), nil
+	}
+	return nil, nil
+}
+
+// References returns the resources ref refers to but does not own, such as the federation
+// and member organizations of a network, and the chaincodes using an endorsepolicy.
+func (g *Graph) References(ctx context.Context, ref Ref) ([]Ref, error) {
+	obj, err := g.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	spec := func(fields ...string) string {
+		return utils.GetNestedString(obj.Object, append([]string{"spec"}, fields...)...)
+	}
+	switch ref.Kind {
+	case KindFederation:
+		return refs(KindOrganization, Members(obj)...), nil
+	case KindNetwork:
+		return append(refs(KindFederation, spec("federation")), refs(KindOrganization, Members(obj)...)...), nil
+	case KindChannel:
+		return append(refs(KindNetwork, spec("network")), refs(KindOrganization, Members(obj)...)...), nil
+	case KindChaincode:
+		result := refs(KindChannel, spec("channel"))
+		result = append(result, refs(KindEndorsePolicy, spec("endorsePolicyRef", "name"))...)
+		return append(result, refs(KindChaincodeBuild, spec("externalBuilder"))...), nil
+	case KindEndorsePolicy:
+		items, err := g.list(ctx, KindChaincode)
+		if err != nil {
+			return nil, err
+		}
+		var chaincodes []string
+		for _, item := range items {
+			if utils.GetNestedString(item.Object, "spec", "endorsePolicyRef", "name") == ref.Name {
+				chaincodes = append(chaincodes, item.GetName())
+			}
+		}
+		sort.Strings(chaincodes)
+		return append(refs(KindChannel, spec("channel")), refs(KindChaincode, chaincodes...)...), nil
+	case KindChaincodeBuild:
+		return refs(KindNetwork, spec("network")), nil
+	}
+	return nil, nil
+}
+
+// Tree returns ref with the resources it owns recursively, down to depth levels. depth < 0 means no limit.
+// Resources which can not be found are marked missing instead of failing the whole tree.
+func (g *Graph) Tree(ctx context.Context, ref Ref, depth int) (*Node, error) {
+	obj, err := g.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	node := &Node{Ref: ref, Status: Status(obj)}
+	if depth == 0 {
+		return node, nil
+	}
+	children, err := g.Children(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if _, err := g.Get(ctx, child); err != nil {
+			node.Children = append(node.Children, &Node{Ref: child, Missing: true})
+			continue
+		}
+		childNode, err := g.Tree(ctx, child, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, childNode)
+	}
+	return node, nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package relation
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kubectl/pkg/describe"
+
+	"github.com/bestchains/bc-cli/pkg/internal/fixture"
+)
+
+func newEvent(name string, kind string, object string, reason string, last time.Time) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion":     "v1",
+		"kind":           "Event",
+		"metadata":       map[string]interface{}{"name": name, "namespace": "default"},
+		"involvedObject": map[string]interface{}{"kind": kind, "name": object},
+		"type":           "Normal",
+		"reason":         reason,
+		"message":        reason + " " + object,
+		"source":         map[string]interface{}{"component": "network-controller"},
+		"lastTimestamp":  last.UTC().Format(time.RFC3339),
+	}}
+}
+
+func newFakeClient() *fake.FakeDynamicClient {
+	now := time.Now()
+	return fixture.NewClient(
+		fixture.NewObject(KindOrganization, "org1", map[string]interface{}{}, map[string]interface{}{"federations": []interface{}{"fed1"}}),
+		fixture.NewObject(KindFederation, "fed1", map[string]interface{}{
+			"members": []interface{}{map[string]interface{}{"name": "org1", "initiator": true}, map[string]interface{}{"name": "org2"}},
+		}, map[string]interface{}{"networks": []interface{}{"net1"}}),
+		fixture.NewObject(KindNetwork, "net1", map[string]interface{}{
+			"federation": "fed1",
+			"members":    []interface{}{map[string]interface{}{"name": "org1", "initiator": true}, map[string]interface{}{"name": "org2"}},
+		}, map[string]interface{}{"channels": []interface{}{"ch1", "ch2"}, "type": "Deployed"}),
+		fixture.NewObject(KindChannel, "ch1", map[string]interface{}{"network": "net1"}, map[string]interface{}{}),
+		fixture.NewObject(KindChaincode, "ch1-basic", map[string]interface{}{
+			"channel":          "ch1",
+			"endorsePolicyRef": map[string]interface{}{"name": "ep1"},
+			"externalBuilder":  "basic-v1",
+		}, map[string]interface{}{}),
+		fixture.NewObject(KindEndorsePolicy, "ep1", map[string]interface{}{"channel": "ch1"}, map[string]interface{}{}),
+		fixture.NewObject(KindEndorsePolicy, "ep2", map[string]interface{}{"channel": "ch3"}, map[string]interface{}{}),
+		newEvent("e1", KindNetwork, "net1", "Created", now.Add(-10*time.Minute)),
+		newEvent("e2", KindNetwork, "net1", "Deployed", now.Add(-5*time.Minute)),
+		newEvent("e3", KindChannel, "net1", "Ignored", now),
+	)
+}
+
+func TestGraph(t *testing.T) {
+	g := NewGraph(newFakeClient())
+	tree, err := g.Tree(context.Background(), Ref{Kind: KindOrganization, Name: "org1"}, -1)
+	assert.NoError(t, err)
+	assert.Equal(t, &Node{Ref: Ref{KindOrganization, "org1"}, Children: []*Node{
+		{Ref: Ref{KindFederation, "fed1"}, Children: []*Node{
+			{Ref: Ref{KindNetwork, "net1"}, Status: "Deployed", Children: []*Node{
+				{Ref: Ref{KindChannel, "ch1"}, Children: []*Node{
+					{Ref: Ref{KindChaincode, "ch1-basic"}},
+					{Ref: Ref{KindEndorsePolicy, "ep1"}},
+				}},
+				{Ref: Ref{KindChannel, "ch2"}, Missing: true},
+			}},
+		}},
+	}}, tree)
+
+	tree, err = g.Tree(context.Background(), Ref{Kind: KindOrganization, Name: "org1"}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Node{{Ref: Ref{KindFederation, "fed1"}}}, tree.Children)
+
+	refs, err := g.References(context.Background(), Ref{Kind: KindEndorsePolicy, Name: "ep1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Ref{{KindChannel, "ch1"}, {KindChaincode, "ch1-basic"}}, refs)
+	refs, err = g.References(context.Background(), Ref{Kind: KindChaincode, Name: "ch1-basic"})
+	assert.NoError(t, err)
+	assert.Equal(t, "channel/ch1 endorsepolicy/ep1 chaincodebuild/basic-v1", refs[0].String()+" "+refs[1].String()+" "+refs[2].String())
+
+	_, err = g.Tree(context.Background(), Ref{Kind: KindNetwork, Name: "unknown"}, -1)
+	assert.Error(t, err)
+}
+
+func TestDescribe(t *testing.T) {
+	cli := newFakeClient()
+	network, err := NewGraph(cli).Get(context.Background(), Ref{Kind: KindNetwork, Name: "net1"})
+	assert.NoError(t, err)
+	out := &bytes.Buffer{}
+	Describe(context.Background(), cli, out, network, func(w describe.PrefixWriter) {
+		w.Write(describe.LEVEL_0, "Name:\t%s\n", network.GetName())
+		w.Write(describe.LEVEL_0, "Members:\t%s\n", FormatMembers(network))
+	})
+	assert.Equal(t, `Name:     net1
+Members:  org1 (initiator), org2
+Related Resources:
+  federation/fed1
+  organization/org1
+  organization/org2
+  channel/ch1
+    chaincode/ch1-basic
+    endorsepolicy/ep1
+  channel/ch2 (not found)
+Events:
+  TYPE    REASON    AGE  FROM                MESSAGE
+  Normal  Created   10m  network-controller  Created net1
+  Normal  Deployed  5m   network-controller  Deployed net1
+`, out.String())
+}
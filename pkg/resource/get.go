@@ -0,0 +1,375 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/kubectl/pkg/cmd/get"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// Options select the resources to get
+type Options struct {
+	// Names of resources, the resources visible to the current user are listed if empty
+	Names []string
+	// LabelSelector and FieldSelector select the resources listed on the server side
+	LabelSelector string
+	FieldSelector string
+	// Filter keeps resources on the client side, such as the channels of a network. Unlike Kind.Visible,
+	// it also applies to resources got by name, which are reported as not found if they are not kept.
+	Filter func(*unstructured.Unstructured) bool
+}
+
+func (o Options) keep(obj *unstructured.Unstructured) bool {
+	return o.Filter == nil || o.Filter(obj)
+}
+
+// Get returns the resources of kind selected by options. A resource which can not be got does not stop the others,
+// the errors are returned as an aggregate together with the resources found.
+func Get(ctx context.Context, cli dynamic.Interface, kind Kind, options Options) ([]unstructured.Unstructured, error) {
+	namespaces := []string{v1.NamespaceAll}
+	if kind.Namespaced() {
+		var err error
+		if namespaces, err = kind.Namespaces(ctx, cli); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		items []unstructured.Unstructured
+		errs  []error
+	)
+	if len(options.Names) == 0 {
+		visible := func(*unstructured.Unstructured) bool { return true }
+		if kind.Visible != nil {
+			var err error
+			if visible, err = kind.Visible(ctx, cli); err != nil {
+				return nil, err
+			}
+		}
+		for _, namespace := range namespaces {
+			list, err := cli.Resource(kind.GVR).Namespace(namespace).List(ctx, v1.ListOptions{LabelSelector: options.LabelSelector, FieldSelector: options.FieldSelector})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for i := range list.Items {
+				if visible(&list.Items[i]) && options.keep(&list.Items[i]) {
+					items = append(items, list.Items[i])
+				}
+			}
+		}
+		return items, utilerrors.Reduce(utilerrors.NewAggregate(errs))
+	}
+
+	for _, name := range utils.RemoveDuplicateForStringSlice(options.Names) {
+		obj, err := getByName(ctx, cli, kind, namespaces, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !options.keep(obj) {
+			errs = append(errs, apierrors.NewNotFound(kind.GVR.GroupResource(), name))
+			continue
+		}
+		items = append(items, *obj)
+	}
+	return items, utilerrors.Reduce(utilerrors.NewAggregate(errs))
+}
+
+// getByName gets the resource named name from the first namespace which has it
+func getByName(ctx context.Context, cli dynamic.Interface, kind Kind, namespaces []string, name string) (*unstructured.Unstructured, error) {
+	for _, namespace := range namespaces {
+		obj, err := cli.Resource(kind.GVR).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+		if err == nil {
+			return obj, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+	return nil, apierrors.NewNotFound(kind.GVR.GroupResource(), name)
+}
+
+// watchFilter returns the filter of the changes of resources selected by options, items are the resources listed
+// before watching. Resources visible to the current user later are kept, as well as later changes of kept ones.
+func watchFilter(ctx context.Context, cli dynamic.Interface, kind Kind, options Options, items []unstructured.Unstructured) (func(*unstructured.Unstructured) bool, error) {
+	inNamespaces := func(*unstructured.Unstructured) bool { return true }
+	if kind.Namespaced() {
+		namespaces, err := kind.Namespaces(ctx, cli)
+		if err != nil {
+			return nil, err
+		}
+		inNamespaces = func(obj *unstructured.Unstructured) bool {
+			return utils.ContainsString(namespaces, obj.GetNamespace())
+		}
+	}
+	if len(options.Names) != 0 {
+		hasName := common.HasName(options.Names)
+		return func(obj *unstructured.Unstructured) bool {
+			return hasName(obj) && inNamespaces(obj) && options.keep(obj)
+		}, nil
+	}
+	visible := func(*unstructured.Unstructured) bool { return true }
+	if kind.Visible != nil {
+		var err error
+		if visible, err = kind.Visible(ctx, cli); err != nil {
+			return nil, err
+		}
+	}
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.GetName())
+	}
+	return common.Seen(names, func(obj *unstructured.Unstructured) bool {
+		return inNamespaces(obj) && visible(obj) && options.keep(obj)
+	}), nil
+}
+
+// ToObj returns items as a single object for printers, a list unless there is exactly one item
+func ToObj(items []unstructured.Unstructured) (runtime.Object, error) {
+	if len(items) == 1 {
+		return &items[0], nil
+	}
+	list := corev1.List{
+		TypeMeta: v1.TypeMeta{
+			Kind:       "List",
+			APIVersion: "v1",
+		},
+		ListMeta: v1.ListMeta{},
+	}
+	for i := range items {
+		list.Items = append(list.Items, runtime.RawExtension{Object: &items[i]})
+	}
+	return common.ListToObj(list)
+}
+
+// GetFlags are the flags shared by getters of resources
+type GetFlags struct {
+	PrintFlags *get.PrintFlags
+	WatchFlags common.WatchFlags
+}
+
+// NewGetFlags returns the flags with the default printer of kubectl get
+func NewGetFlags() *GetFlags {
+	return &GetFlags{PrintFlags: get.NewGetPrintFlags()}
+}
+
+// AddFlags adds the printer flags, --watch and --watch-only to cmd
+func (f *GetFlags) AddFlags(cmd *cobra.Command) {
+	f.PrintFlags.AddFlags(cmd)
+	f.WatchFlags.AddFlags(cmd)
+}
+
+// ToPrinter returns the printer of resources of kind. Resources are printed as tables with the columns of kind
+// in the default and wide output formats, sorted by --sort-by unless changes are watched.
+func (f *GetFlags) ToPrinter(kind Kind) (printers.ResourcePrinter, error) {
+	p, err := f.PrintFlags.ToPrinter()
+	if err != nil {
+		return nil, err
+	}
+	if sortBy := f.sortBy(); sortBy != "" && !f.WatchFlags.Enabled() {
+		p = &get.SortingPrinter{Delegate: p, SortField: sortBy, Decoder: unstructured.UnstructuredJSONScheme}
+	}
+	if format := f.outputFormat(); (format == "" || format == "wide") && len(kind.Columns) > 0 {
+		p = &TablePrinter{Columns: kind.Columns, Delegate: p}
+	}
+	return p, nil
+}
+
+func (f *GetFlags) outputFormat() string {
+	if f.PrintFlags.OutputFormat == nil {
+		return ""
+	}
+	return *f.PrintFlags.OutputFormat
+}
+
+func (f *GetFlags) sortBy() string {
+	if f.PrintFlags.HumanReadableFlags.SortBy == nil {
+		return ""
+	}
+	return *f.PrintFlags.HumanReadableFlags.SortBy
+}
+
+// Run prints the resources of kind selected by options to option.Out, then watches for changes if requested.
+// The error of each resource which can not be got is printed to option.ErrOut without stopping the others,
+// an aggregate of them is returned so that the command exits with a non-zero code.
+func (f *GetFlags) Run(ctx context.Context, cli dynamic.Interface, option common.Options, kind Kind, options Options) error {
+	p, err := f.ToPrinter(kind)
+	if err != nil {
+		fmt.Fprintln(option.ErrOut, err)
+		return err
+	}
+	if f.sortBy() != "" && f.WatchFlags.Enabled() {
+		fmt.Fprintln(option.ErrOut, "warning: --watch or --watch-only requested, --sort-by will be ignored")
+	}
+	items, getErr := Get(ctx, cli, kind, options)
+	if getErr != nil {
+		PrintErrors(option, getErr)
+		if items == nil && !f.WatchFlags.Enabled() {
+			return getErr
+		}
+	}
+
+	if !f.WatchFlags.WatchOnly {
+		obj, err := ToObj(items)
+		if err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		if err := p.PrintObj(obj, option.Out); err != nil {
+			fmt.Fprintln(option.ErrOut, err)
+			return err
+		}
+		if len(items) == 0 && getErr == nil && f.outputFormat() == "" {
+			fmt.Fprintln(option.ErrOut, "No resources found")
+		}
+	}
+	if !f.WatchFlags.Enabled() {
+		return getErr
+	}
+
+	filter, err := watchFilter(ctx, cli, kind, options, items)
+	if err != nil {
+		fmt.Fprintln(option.ErrOut, err)
+		return err
+	}
+	watchOptions := v1.ListOptions{}
+	if len(options.Names) == 0 {
+		watchOptions = v1.ListOptions{LabelSelector: options.LabelSelector, FieldSelector: options.FieldSelector}
+	}
+	if err := watchKind(ctx, cli, kind, watchOptions, filter, common.NewWatchPrinter(p, option.Out).Handler()); err != nil {
+		fmt.Fprintln(option.ErrOut, err)
+		return utilerrors.NewAggregate([]error{getErr, err})
+	}
+	return getErr
+}
+
+// watchKind streams the changes of resources of kind like common.Watch. Namespaced kinds are watched in each
+// of their namespaces, as regular users can not watch across all namespaces, and the events are handled one by one.
+func watchKind(ctx context.Context, cli dynamic.Interface, kind Kind, options v1.ListOptions, filter func(*unstructured.Unstructured) bool, handle common.WatchHandler) error {
+	if !kind.Namespaced() {
+		return common.Watch(ctx, cli.Resource(kind.GVR), options, filter, handle)
+	}
+	namespaces, err := kind.Namespaces(ctx, cli)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var mu sync.Mutex
+	serialized := func(eventType watch.EventType, obj *unstructured.Unstructured) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return handle(eventType, obj)
+	}
+	errs := make(chan error, len(namespaces))
+	for _, namespace := range namespaces {
+		go func(namespace string) {
+			errs <- common.Watch(ctx, cli.Resource(kind.GVR).Namespace(namespace), options, filter, serialized)
+		}(namespace)
+	}
+	// the first error stops the watches of the other namespaces
+	for range namespaces {
+		if e := <-errs; e != nil && err == nil {
+			err = e
+			cancel()
+		}
+	}
+	return err
+}
+
+// PrintErrors prints each error in err on its own line, such as the aggregate returned by Get
+func PrintErrors(option common.Options, err error) {
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		for _, e := range agg.Errors() {
+			fmt.Fprintln(option.ErrOut, e)
+		}
+		return
+	}
+	fmt.Fprintln(option.ErrOut, err)
+}
+
+// NewGetCmd returns the getter of kind, which lists the resources visible to the current user
+// or gets resources by name.
+func NewGetCmd(option common.Options, kind Kind) *cobra.Command {
+	var labelSelector, fieldSelector string
+	flags := NewGetFlags()
+	cmd := &cobra.Command{
+		Use:     kind.Name + " [NAME]...",
+		Aliases: kind.Names()[1:],
+		Short:   fmt.Sprintf("Get a list of %s", kind.GVR.Resource),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			return flags.Run(cmd.Context(), cli, option, kind, Options{Names: args, LabelSelector: labelSelector, FieldSelector: fieldSelector})
+		},
+	}
+	flags.AddFlags(cmd)
+	cmd.Flags().StringVar(&fieldSelector, "field-selector", "", "Selector (field query) to filter on, supports '=', '==', and '!='.(e.g. --field-selector key1=value1,key2=value2). The server only supports a limited number of field queries per type.")
+	cmdutil.AddLabelSelectorFlagVar(cmd, &labelSelector)
+	return cmd
+}
+
+// AddGetCmds adds a getter to cmd for each kind in registry. Kinds with a dedicated getter in cmd
+// keep it, the names of the kind which the getter does not accept yet are added to its aliases.
+func AddGetCmds(cmd *cobra.Command, option common.Options, registry *Registry) {
+	for _, kind := range registry.Kinds() {
+		if dedicated := findCmd(cmd, kind); dedicated != nil {
+			for _, name := range kind.Names() {
+				if dedicated.Name() != name && !dedicated.HasAlias(name) {
+					dedicated.Aliases = append(dedicated.Aliases, name)
+				}
+			}
+			continue
+		}
+		cmd.AddCommand(common.RequireLogin(NewGetCmd(option, kind)))
+	}
+}
+
+// findCmd returns the subcommand of cmd named by any name of kind
+func findCmd(cmd *cobra.Command, kind Kind) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		for _, name := range kind.Names() {
+			if c.Name() == name || c.HasAlias(name) {
+				return c
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resource gets bestchains resources of any kind in a registry, which maps the names
+// accepted on the command line, such as fed or ccb, to resources, their scope and the filters of the current user.
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// Kind describes a kind of bestchains resources
+type Kind struct {
+	// Kind is the kind of resources, such as Federation
+	Kind string
+	// Name is the singular name of resources, such as federation
+	Name string
+	// Aliases are the other names of resources besides the name and the plural name in GVR, such as fed
+	Aliases []string
+	GVR     schema.GroupVersionResource
+	// Namespaces returns the namespaces which resources are got from, such as the organizations of the current user.
+	// It is nil for cluster-scoped kinds.
+	Namespaces func(ctx context.Context, cli dynamic.Interface) ([]string, error)
+	// Visible returns a filter keeping the resources visible to the current user when resources are listed,
+	// resources got by name are not filtered. A nil Visible keeps all resources.
+	Visible func(ctx context.Context, cli dynamic.Interface) (func(*unstructured.Unstructured) bool, error)
+	// Columns are printed between NAME and AGE in the default output format, only NAME and AGE are printed if empty
+	Columns []Column
+}
+
+// Names returns all names of k: the name, the plural name and the aliases
+func (k Kind) Names() []string {
+	names := []string{k.Name}
+	if k.GVR.Resource != k.Name {
+		names = append(names, k.GVR.Resource)
+	}
+	return append(names, k.Aliases...)
+}
+
+// Namespaced reports whether resources of k are in namespaces
+func (k Kind) Namespaced() bool {
+	return k.Namespaces != nil
+}
+
+// Registry looks up kinds by their names
+type Registry struct {
+	kinds []Kind
+	names map[string]int
+}
+
+// NewRegistry returns a registry of kinds, it panics if a name is used by more than one kind
+func NewRegistry(kinds ...Kind) *Registry {
+	r := &Registry{names: make(map[string]int)}
+	for _, k := range kinds {
+		for _, name := range k.Names() {
+			name = strings.ToLower(name)
+			if i, ok := r.names[name]; ok {
+				panic(fmt.Sprintf("name %s of kind %s is already used by kind %s", name, k.Kind, r.kinds[i].Kind))
+			}
+			r.names[name] = len(r.kinds)
+		}
+		r.kinds = append(r.kinds, k)
+	}
+	return r
+}
+
+// Kinds returns the kinds in r in the order they are registered
+func (r *Registry) Kinds() []Kind {
+	return append([]Kind(nil), r.kinds...)
+}
+
+// Lookup returns the kind named name, name is case-insensitive
+func (r *Registry) Lookup(name string) (Kind, error) {
+	i, ok := r.names[strings.ToLower(name)]
+	if !ok {
+		return Kind{}, fmt.Errorf("unknown kind %q, supported kinds: %s", name, strings.Join(r.Names(), ","))
+	}
+	return r.kinds[i], nil
+}
+
+// ForKind returns the kind whose Kind is kind, such as Federation
+func (r *Registry) ForKind(kind string) (Kind, bool) {
+	for _, k := range r.kinds {
+		if k.Kind == kind {
+			return k, true
+		}
+	}
+	return Kind{}, false
+}
+
+// Names returns all names of kinds in r, sorted
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.names))
+	for name := range r.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
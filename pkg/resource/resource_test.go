@@ -0,0 +1,262 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+var (
+	fedKind = Kind{
+		Kind:    "Federation",
+		Name:    "federation",
+		Aliases: []string{"fed"},
+		GVR:     schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.FederationResource},
+		Visible: func(context.Context, dynamic.Interface) (func(*unstructured.Unstructured) bool, error) {
+			return func(obj *unstructured.Unstructured) bool { return obj.GetLabels()["visible"] == "true" }, nil
+		},
+	}
+	voteKind = Kind{
+		Kind: "Vote",
+		Name: "vote",
+		GVR:  schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Vote},
+		Namespaces: func(context.Context, dynamic.Interface) ([]string, error) {
+			return []string{"org1", "org2"}, nil
+		},
+	}
+)
+
+func newObject(kind string, namespace string, name string, visible bool) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(common.IBPGroup + "/" + common.IBPVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	if visible {
+		obj.SetLabels(map[string]string{"visible": "true"})
+	}
+	return obj
+}
+
+func newFakeClient() *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		fedKind.GVR:  "FederationList",
+		voteKind.GVR: "VoteList",
+	},
+		newObject("Federation", "", "fed1", true),
+		newObject("Federation", "", "fed2", false),
+		newObject("Federation", "", "fed3", true),
+		newObject("Vote", "org1", "vote1", false),
+		newObject("Vote", "org2", "vote2", false),
+		newObject("Vote", "org3", "vote3", false),
+	)
+}
+
+func names(items []unstructured.Unstructured) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.GetName())
+	}
+	return result
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(fedKind, voteKind)
+	for _, name := range []string{"fed", "Federation", "federations", "votes"} {
+		_, err := r.Lookup(name)
+		assert.NoError(t, err, name)
+	}
+	k, err := r.Lookup("FED")
+	assert.NoError(t, err)
+	assert.Equal(t, "Federation", k.Kind)
+	_, err = r.Lookup("pod")
+	assert.ErrorContains(t, err, "supported kinds: fed,federation,federations,vote,votes")
+
+	k, ok := r.ForKind("Vote")
+	assert.True(t, ok)
+	assert.True(t, k.Namespaced())
+
+	assert.Panics(t, func() { NewRegistry(fedKind, Kind{Kind: "Other", Name: "fed"}) })
+}
+
+func TestGet(t *testing.T) {
+	cli := newFakeClient()
+	ctx := context.Background()
+
+	// only visible resources are listed
+	items, err := Get(ctx, cli, fedKind, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fed1", "fed3"}, names(items))
+
+	// resources got by name are not filtered by visibility, missing ones do not stop the others
+	items, err = Get(ctx, cli, fedKind, Options{Names: []string{"fed2", "fed4", "fed1", "fed2", "fed5"}})
+	assert.Equal(t, []string{"fed2", "fed1"}, names(items))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "fed4")
+	assert.Contains(t, err.Error(), "fed5")
+
+	// the filter applies to resources got by name as well
+	notFed3 := func(obj *unstructured.Unstructured) bool { return obj.GetName() != "fed3" }
+	items, err = Get(ctx, cli, fedKind, Options{Names: []string{"fed1", "fed3"}, Filter: notFed3})
+	assert.Equal(t, []string{"fed1"}, names(items))
+	assert.ErrorContains(t, err, "fed3")
+	items, err = Get(ctx, cli, fedKind, Options{Filter: notFed3})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fed1"}, names(items))
+
+	// namespaced resources are got from the namespaces of the kind only
+	items, err = Get(ctx, cli, voteKind, Options{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"vote1", "vote2"}, names(items))
+	items, err = Get(ctx, cli, voteKind, Options{Names: []string{"vote2", "vote3"}})
+	assert.Equal(t, []string{"vote2"}, names(items))
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestRun(t *testing.T) {
+	cli := newFakeClient()
+	out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+	option := common.Options{IOStreams: genericclioptions.IOStreams{Out: out, ErrOut: errOut}}
+
+	flags := NewGetFlags()
+	flags.AddFlags(&cobra.Command{})
+	*flags.PrintFlags.OutputFormat = "name"
+	err := flags.Run(context.Background(), cli, option, fedKind, Options{Names: []string{"fed1", "fed4"}})
+	assert.Error(t, err)
+	assert.Equal(t, "federation.ibp.com/fed1\n", out.String())
+	assert.Contains(t, errOut.String(), `"fed4" not found`)
+
+	out.Reset()
+	errOut.Reset()
+	err = flags.Run(context.Background(), cli, option, fedKind, Options{Names: []string{"fed4"}})
+	assert.Error(t, err)
+	assert.Empty(t, out.String())
+}
+
+func TestWatchKind(t *testing.T) {
+	cli := newFakeClient()
+	var (
+		mu         sync.Mutex
+		namespaces []string
+	)
+	cli.PrependWatchReactor("votes", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		namespaces = append(namespaces, action.GetNamespace())
+		return false, nil, nil
+	})
+
+	// votes are watched in the namespaces of the kind instead of across all namespaces
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan string, 1)
+	done := make(chan error)
+	go func() {
+		done <- watchKind(ctx, cli, voteKind, v1.ListOptions{}, nil, func(_ watch.EventType, obj *unstructured.Unstructured) error {
+			events <- obj.GetNamespace() + "/" + obj.GetName()
+			return nil
+		})
+	}()
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(namespaces) == 2
+	}, time.Second, 10*time.Millisecond)
+	_, err := cli.Resource(voteKind.GVR).Namespace("org2").Create(ctx, newObject("Vote", "org2", "vote4", false), v1.CreateOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "org2/vote4", <-events)
+	cancel()
+	assert.NoError(t, <-done)
+
+	sort.Strings(namespaces)
+	assert.Equal(t, []string{"org1", "org2"}, namespaces)
+}
+
+func TestAddGetCmds(t *testing.T) {
+	cmd := &cobra.Command{Use: "get"}
+	cmd.AddCommand(&cobra.Command{Use: "fed"})
+	AddGetCmds(cmd, common.Options{}, NewRegistry(fedKind, voteKind))
+
+	fed, _, err := cmd.Find([]string{"federations"})
+	assert.NoError(t, err)
+	assert.Equal(t, "fed", fed.Name())
+	vote, _, err := cmd.Find([]string{"votes"})
+	assert.NoError(t, err)
+	assert.Equal(t, "vote", vote.Name())
+	assert.True(t, common.LoginRequired(vote))
+}
+
+func TestTable(t *testing.T) {
+	columns := []Column{
+		{Name: "Channel", Value: Field("spec", "channel")},
+		{Name: "Peers", Value: Count("spec", "peers")},
+		{Name: "Value", Wide: true, Value: Field("spec", "value")},
+	}
+	newItem := func(name string, channel string, peers int) unstructured.Unstructured {
+		obj := newObject("EndorsePolicy", "", name, false)
+		var items []interface{}
+		for i := 0; i < peers; i++ {
+			items = append(items, map[string]interface{}{"name": "peer"})
+		}
+		obj.Object["spec"] = map[string]interface{}{"channel": channel, "peers": items, "value": "AND('org1.member')"}
+		return *obj
+	}
+	list := &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+		newItem("ep2", "ch2", 2), newItem("ep1", "", 1),
+	}}
+
+	print := func(format string, sortBy string) string {
+		flags := NewGetFlags()
+		flags.AddFlags(&cobra.Command{})
+		*flags.PrintFlags.OutputFormat = format
+		*flags.PrintFlags.HumanReadableFlags.SortBy = sortBy
+		p, err := flags.ToPrinter(Kind{Columns: columns})
+		assert.NoError(t, err)
+		out := &bytes.Buffer{}
+		assert.NoError(t, p.PrintObj(list.DeepCopy(), out))
+		return out.String()
+	}
+
+	assert.Equal(t, `NAME   CHANNEL   PEERS   AGE
+ep2    ch2       2       <unknown>
+ep1    <none>    1       <unknown>
+`, print("", ""))
+	assert.Equal(t, `NAME   CHANNEL   PEERS   VALUE                AGE
+ep1    <none>    1       AND('org1.member')   <unknown>
+ep2    ch2       2       AND('org1.member')   <unknown>
+`, print("wide", "{.metadata.name}"))
+	assert.Equal(t, "endorsepolicy.ibp.com/ep1\nendorsepolicy.ibp.com/ep2\n", print("name", ".metadata.name"))
+}
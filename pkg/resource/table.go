@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"io"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+// Column is a column of the table of resources between NAME and AGE,
+// like the additional printer columns of a CRD which are printed by the server
+type Column struct {
+	Name string
+	// Wide columns are only printed with -o wide
+	Wide bool
+	// Value returns the cell of a resource, a string or an integer so that the column can be sorted by --sort-by
+	Value func(obj *unstructured.Unstructured) interface{}
+}
+
+// Field returns the value of the string field at path, <none> if it is empty
+func Field(path ...string) func(*unstructured.Unstructured) interface{} {
+	return func(obj *unstructured.Unstructured) interface{} {
+		return OrNone(utils.GetNestedString(obj.Object, path...))
+	}
+}
+
+// Count returns the number of items in the slice field at path
+func Count(path ...string) func(*unstructured.Unstructured) interface{} {
+	return func(obj *unstructured.Unstructured) interface{} {
+		items, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		return int64(len(items))
+	}
+}
+
+// OrNone returns s, or <none> if s is empty
+func OrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// Table returns the table of items with columns, the row of each item refers to the item
+// so that rows can be sorted by fields of items
+func Table(columns []Column, items []unstructured.Unstructured, now time.Time) *v1.Table {
+	table := &v1.Table{
+		TypeMeta: v1.TypeMeta{Kind: "Table", APIVersion: v1.SchemeGroupVersion.String()},
+		ColumnDefinitions: []v1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+		},
+	}
+	for _, c := range columns {
+		definition := v1.TableColumnDefinition{Name: c.Name, Type: "string"}
+		if c.Wide {
+			definition.Priority = 1
+		}
+		table.ColumnDefinitions = append(table.ColumnDefinitions, definition)
+	}
+	table.ColumnDefinitions = append(table.ColumnDefinitions, v1.TableColumnDefinition{Name: "Age", Type: "string"})
+
+	for i := range items {
+		item := &items[i]
+		cells := []interface{}{item.GetName()}
+		for _, c := range columns {
+			cells = append(cells, c.Value(item))
+		}
+		cells = append(cells, age(item.GetCreationTimestamp(), now))
+		table.Rows = append(table.Rows, v1.TableRow{Cells: cells, Object: runtime.RawExtension{Object: item}})
+	}
+	return table
+}
+
+func age(created v1.Time, now time.Time) string {
+	if created.IsZero() {
+		return "<unknown>"
+	}
+	return duration.HumanDuration(now.Sub(created.Time))
+}
+
+// TablePrinter prints resources and lists of resources as tables with Columns, other objects are passed through.
+// Delegate should be a human readable printer which prints tables, it can be wrapped by a sorting printer.
+type TablePrinter struct {
+	Columns  []Column
+	Delegate printers.ResourcePrinter
+}
+
+// PrintObj prints obj as a table to w
+func (p *TablePrinter) PrintObj(obj runtime.Object, w io.Writer) error {
+	switch o := obj.(type) {
+	case *unstructured.Unstructured:
+		return p.Delegate.PrintObj(Table(p.Columns, []unstructured.Unstructured{*o}, time.Now()), w)
+	case *unstructured.UnstructuredList:
+		return p.Delegate.PrintObj(Table(p.Columns, o.Items, time.Now()), w)
+	}
+	return p.Delegate.PrintObj(obj, w)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testserver
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bestchains/bc-cli/pkg/client/depository"
+)
+
+// certificatePDF renders a minimal single page pdf which lists the fields of d.
+// It is not the real certificate of bc-saas, but any pdf reader can open it.
+func certificatePDF(d depository.Depository, style string) []byte {
+	title := "Depository Certificate"
+	if style == "CN" {
+		// the builtin fonts of pdf have no chinese glyphs
+		title = "Depository Certificate (CN)"
+	}
+	lines := []string{
+		title,
+		"KID: " + d.KID,
+		"Name: " + d.Name,
+		"Content ID: " + d.ContentID,
+		"Content Type: " + d.ContentType,
+		"Platform: " + d.Platform,
+		"Owner: " + d.Owner,
+		fmt.Sprintf("Block Number: %d", d.BlockNumber),
+		"Trusted Timestamp: " + time.Unix(d.TrustedTimestamp, 0).UTC().Format(time.RFC3339),
+	}
+	content := &bytes.Buffer{}
+	content.WriteString("BT /F1 12 Tf 50 780 Td 16 TL\n")
+	for _, line := range lines {
+		fmt.Fprintf(content, "(%s) Tj T*\n", escapePDFString(line))
+	}
+	content.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+	pdf := &bytes.Buffer{}
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(pdf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := pdf.Len()
+	fmt.Fprintf(pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return pdf.Bytes()
+}
+
+func escapePDFString(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+// NewServeCmd creates a command which serves an in-memory bc-saas server until interrupted
+func NewServeCmd(option common.Options) *cobra.Command {
+	var depositoryAddr, marketAddr string
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve an in-memory bc-saas server for offline testing and demos",
+		Long: `Serve an in-memory bc-saas server which implements the depository and market apis.
+The data is shared by both listeners and lost when the server stops.`,
+		Example: `  # serve on the default hosts of 'create depository' and 'create market repo'
+  bc-cli dev serve
+
+  # serve on a random port
+  bc-cli dev serve --depository-addr 127.0.0.1:0 --market-addr ""`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+			return Serve(ctx, option, New(), depositoryAddr, marketAddr)
+		},
+	}
+	cmd.Flags().StringVar(&depositoryAddr, "depository-addr", "127.0.0.1:9999", "address to serve the depository apis, empty to disable")
+	cmd.Flags().StringVar(&marketAddr, "market-addr", "127.0.0.1:9998", "address to serve the market apis, empty to disable")
+	return cmd
+}
+
+// Serve serves handler on addrs until ctx is done. Every address is listened on before any server starts,
+// so the listeners already opened are closed if one of them fails.
+func Serve(ctx context.Context, option common.Options, handler http.Handler, addrs ...string) error {
+	var listeners []net.Listener
+	for _, addr := range addrs {
+		if addr == "" {
+			continue
+		}
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, l)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, l := range listeners {
+		l := l
+		srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+		fmt.Fprintf(option.Out, "serving on http://%s\n", l.Addr())
+		g.Go(func() error {
+			if err := srv.Serve(l); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+		g.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return srv.Shutdown(shutdownCtx)
+		})
+	}
+	return g.Wait()
+}
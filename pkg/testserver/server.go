@@ -0,0 +1,369 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testserver provides an in-memory bc-saas server which implements the depository
+// and market apis, so that bc-cli can be tested and demonstrated without a blockchain.
+package testserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	bccontext "github.com/bestchains/bestchains-contracts/library/context"
+
+	"github.com/bestchains/bc-cli/pkg/client/depository"
+	"github.com/bestchains/bc-cli/pkg/client/market"
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+const (
+	// defaultPageSize is used when the list request has no size
+	defaultPageSize = 10
+	// operator of untrusted depositories, which are stored without an account endorsement
+	untrustedOperator = "untrusted"
+)
+
+// Server is an in-memory bc-saas server. It is safe for concurrent use.
+type Server struct {
+	mu sync.Mutex
+	// nonces of accounts, keyed by the nonce path and account address
+	nonces       map[string]map[string]uint64
+	depositories []depository.Depository
+	repositories []market.Repository
+	blockNumber  uint64
+
+	mux *http.ServeMux
+}
+
+// New returns an empty server
+func New() *Server {
+	s := &Server{
+		nonces: map[string]map[string]uint64{
+			common.DepositoryCurrentNonce: {},
+			common.MarketCurrentNonce:     {},
+		},
+		mux: http.NewServeMux(),
+	}
+	s.mux.HandleFunc(common.DepositoryCurrentNonce, s.currentNonce(common.DepositoryCurrentNonce))
+	s.mux.HandleFunc(common.CreateDepository, s.putValue)
+	s.mux.HandleFunc(common.CreateUntrustedDepository, s.putUntrustValue)
+	s.mux.HandleFunc(common.ListDepository, s.listDepositories)
+	s.mux.HandleFunc(common.ListDepository+"/", s.getDepository)
+	s.mux.HandleFunc(common.MarketCurrentNonce, s.currentNonce(common.MarketCurrentNonce))
+	s.mux.HandleFunc(common.CreateRepository, s.createRepo)
+	s.mux.HandleFunc(common.ListRepositories, s.listRepos)
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) currentNonce(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		account := r.URL.Query().Get("account")
+		if account == "" {
+			writeError(w, http.StatusBadRequest, "account is required")
+			return
+		}
+		s.mu.Lock()
+		n := s.nonces[path][account]
+		s.mu.Unlock()
+		writeJSON(w, http.StatusOK, map[string]uint64{"nonce": n})
+	}
+}
+
+// verify checks the signature of the message against args and the current nonce of the sender,
+// the nonce of the sender is increased if the message is valid. The caller must hold s.mu.
+func (s *Server) verify(noncePath string, message string, args ...string) (string, error) {
+	msg := &bccontext.Message{}
+	if err := msg.FromBase64EncodedStr(message); err != nil {
+		return "", err
+	}
+	sender, err := msg.VerifyAgainstArgs(args...)
+	if err != nil {
+		return "", err
+	}
+	addr := sender.String()
+	if curr := s.nonces[noncePath][addr]; msg.Nonce != curr {
+		return "", fmt.Errorf("invalid nonce %d, expect %d", msg.Nonce, curr)
+	}
+	s.nonces[noncePath][addr]++
+	return addr, nil
+}
+
+func (s *Server) putValue(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	value := r.PostForm.Get("value")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sender, err := s.verify(common.DepositoryCurrentNonce, r.PostForm.Get("message"), value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.storeValue(w, sender, value)
+}
+
+func (s *Server) putUntrustValue(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storeValue(w, untrustedOperator, r.PostForm.Get("value"))
+}
+
+// storeValue decodes the base64 encoded value and stores it. The caller must hold s.mu.
+func (s *Server) storeValue(w http.ResponseWriter, operator string, encoded string) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "value is not base64 encoded: "+err.Error())
+		return
+	}
+	value := depository.ValueDepository{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid value: "+err.Error())
+		return
+	}
+	if value.Name == "" || value.ContentID == "" {
+		writeError(w, http.StatusBadRequest, "name and contentID are required")
+		return
+	}
+	timestamp, err := strconv.ParseInt(value.TrustedTimestamp, 10, 64)
+	if err != nil {
+		timestamp = time.Now().Unix()
+	}
+
+	s.blockNumber++
+	index := len(s.depositories)
+	d := depository.Depository{
+		Index:            strconv.Itoa(index),
+		KID:              generateID(strconv.Itoa(index), operator, encoded),
+		Platform:         value.Platform,
+		Operator:         operator,
+		Owner:            operator,
+		BlockNumber:      s.blockNumber,
+		Name:             value.Name,
+		ContentName:      value.Name,
+		ContentID:        value.ContentID,
+		ContentType:      value.ContentType,
+		TrustedTimestamp: timestamp,
+	}
+	s.depositories = append(s.depositories, d)
+	writeJSON(w, http.StatusOK, depository.PutValueResponse{KID: d.KID})
+}
+
+func (s *Server) listDepositories(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	query := r.URL.Query()
+	from, size, err := pagination(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	kid, name, contentName := query.Get("kid"), query.Get("name"), query.Get("contentName")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matched := make([]depository.Depository, 0)
+	// newest first
+	for i := len(s.depositories) - 1; i >= 0; i-- {
+		d := s.depositories[i]
+		if (kid != "" && d.KID != kid) ||
+			(name != "" && !strings.Contains(d.Name, name)) ||
+			(contentName != "" && !strings.Contains(d.ContentName, contentName)) {
+			continue
+		}
+		matched = append(matched, d)
+	}
+	list := depository.DepositoryList{Data: page(matched, from, size), Count: int64(len(matched))}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// getDepository serves both /basic/depositories/<kid> and /basic/depositories/certificate/<kid>
+func (s *Server) getDepository(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	kid := strings.TrimPrefix(r.URL.Path, common.ListDepository+"/")
+	certificate := strings.HasPrefix(kid, "certificate/")
+	kid = strings.TrimPrefix(kid, "certificate/")
+
+	s.mu.Lock()
+	var found *depository.Depository
+	for i := range s.depositories {
+		if s.depositories[i].KID == kid {
+			d := s.depositories[i]
+			found = &d
+			break
+		}
+	}
+	s.mu.Unlock()
+	if found == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("depository %s not found", kid))
+		return
+	}
+	if !certificate {
+		writeJSON(w, http.StatusOK, found)
+		return
+	}
+	style := r.URL.Query().Get("style")
+	if style != "CN" && style != "ENG" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown certificate style %q", style))
+		return
+	}
+	pdf := certificatePDF(*found, style)
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pdf)
+}
+
+func (s *Server) createRepo(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	repoURL := r.PostForm.Get("url")
+	if _, err := url.ParseRequestURI(repoURL); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid repository url: "+err.Error())
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, repo := range s.repositories {
+		if repo.URL == repoURL {
+			writeError(w, http.StatusConflict, fmt.Sprintf("repository %s already exists", repoURL))
+			return
+		}
+	}
+	sender, err := s.verify(common.MarketCurrentNonce, r.PostForm.Get("message"), repoURL)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	repo := market.Repository{
+		ID:    generateID(strconv.Itoa(len(s.repositories)), sender, repoURL),
+		Owner: sender,
+		URL:   repoURL,
+	}
+	s.repositories = append(s.repositories, repo)
+	writeJSON(w, http.StatusOK, market.CreateRepoResponse{ID: repo.ID})
+}
+
+func (s *Server) listRepos(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	query := r.URL.Query()
+	from, size, err := pagination(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	owner := query.Get("owner")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matched := make([]market.Repository, 0)
+	for _, repo := range s.repositories {
+		if owner != "" && repo.Owner != owner {
+			continue
+		}
+		matched = append(matched, repo)
+	}
+	list := market.RepositoryList{Data: page(matched, from, size), Count: int64(len(matched))}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// generateID returns a stable hex id of parts
+func generateID(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "/")))
+	return hex.EncodeToString(sum[:])
+}
+
+func pagination(query url.Values) (from int, size int, err error) {
+	size = defaultPageSize
+	if v := query.Get("from"); v != "" {
+		if from, err = strconv.Atoi(v); err != nil || from < 0 {
+			return 0, 0, fmt.Errorf("invalid from %q", v)
+		}
+	}
+	if v := query.Get("size"); v != "" {
+		if size, err = strconv.Atoi(v); err != nil || size <= 0 {
+			return 0, 0, fmt.Errorf("invalid size %q", v)
+		}
+	}
+	return from, size, nil
+}
+
+func page[T any](items []T, from, size int) []T {
+	if from >= len(items) {
+		return []T{}
+	}
+	end := from + size
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[from:end]
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, msg string) {
+	writeJSON(w, statusCode, map[string]string{"msg": msg})
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/bestchains/bc-cli/pkg/account"
+	"github.com/bestchains/bc-cli/pkg/client/depository"
+	"github.com/bestchains/bc-cli/pkg/client/market"
+	"github.com/bestchains/bc-cli/pkg/common"
+	uhttp "github.com/bestchains/bc-cli/pkg/utils/http"
+)
+
+func newTestAccount(t *testing.T) *account.Account {
+	wallet, err := account.NewLocalWallet(t.TempDir())
+	assert.NoError(t, err)
+	acc, err := account.NewAccount()
+	assert.NoError(t, err)
+	assert.NoError(t, wallet.StoreAccount(acc))
+	acc, err = wallet.GetAccount(acc.Address)
+	assert.NoError(t, err)
+	return acc
+}
+
+func TestDepository(t *testing.T) {
+	server := httptest.NewServer(New())
+	defer server.Close()
+	ctx := context.Background()
+	acc := newTestAccount(t)
+	client, err := depository.NewClient(server.URL)
+	assert.NoError(t, err)
+
+	kids := make([]string, 0)
+	for i := 0; i < 3; i++ {
+		value := depository.ValueDepository{Name: fmt.Sprintf("doc-%d", i), ContentID: "hash", TrustedTimestamp: "123", Platform: "bestchains"}
+		resp, err := client.PutValue(ctx, acc, value)
+		assert.NoError(t, err)
+		kids = append(kids, resp.KID)
+	}
+	resp, err := client.PutUntrustValue(ctx, depository.ValueDepository{Name: "untrusted", ContentID: "hash"})
+	assert.NoError(t, err)
+
+	n, err := client.CurrentNonce(ctx, acc.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), n)
+
+	d, err := client.Get(ctx, kids[1])
+	assert.NoError(t, err)
+	assert.Equal(t, "doc-1", d.Name)
+	assert.Equal(t, acc.Address, d.Owner)
+	assert.Equal(t, int64(123), d.TrustedTimestamp)
+
+	d, err = client.Get(ctx, resp.KID)
+	assert.NoError(t, err)
+	assert.Equal(t, untrustedOperator, d.Operator)
+
+	_, err = client.Get(ctx, "unknown")
+	assert.True(t, uhttp.IsNotFound(err))
+
+	list, err := client.List(ctx, depository.ListOptions{From: 1, Size: 2, Name: "doc"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), list.Count)
+	assert.Equal(t, 2, len(list.Data))
+	assert.Equal(t, kids[1], list.Data[0].KID)
+	assert.Equal(t, kids[0], list.Data[1].KID)
+
+	body, size, err := client.Certificate(ctx, kids[0], "ENG")
+	assert.NoError(t, err)
+	defer body.Close()
+	pdf, err := io.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(pdf)), size)
+	assert.True(t, strings.HasPrefix(string(pdf), "%PDF-"))
+	assert.Contains(t, string(pdf), kids[0])
+}
+
+func TestReplayedMessage(t *testing.T) {
+	server := httptest.NewServer(New())
+	defer server.Close()
+	acc := newTestAccount(t)
+
+	msg, err := acc.GenerateAndSignMessage(0, "https://github.com/bestchains/bc-cli")
+	assert.NoError(t, err)
+	form := url.Values{"message": {msg}, "url": {"https://github.com/bestchains/bc-cli"}}
+	resp, err := http.PostForm(server.URL+common.CreateRepository, form)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// the same signed message must not be accepted twice
+	form.Set("url", "https://github.com/bestchains/bc-saas")
+	resp, err = http.PostForm(server.URL+common.CreateRepository, form)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestMarket(t *testing.T) {
+	server := httptest.NewServer(New())
+	defer server.Close()
+	ctx := context.Background()
+	alice, bob := newTestAccount(t), newTestAccount(t)
+	client, err := market.NewClient(server.URL)
+	assert.NoError(t, err)
+
+	created, err := client.CreateRepo(ctx, alice, "https://github.com/bestchains/bc-cli")
+	assert.NoError(t, err)
+	_, err = client.CreateRepo(ctx, bob, "https://github.com/bestchains/bc-saas")
+	assert.NoError(t, err)
+	_, err = client.CreateRepo(ctx, bob, "https://github.com/bestchains/bc-cli")
+	assert.True(t, uhttp.IsStatus(err, http.StatusConflict))
+
+	list, err := client.ListRepos(ctx, market.ListOptions{Owner: alice.Address})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), list.Count)
+	assert.Equal(t, created.ID, list.Data[0].ID)
+
+	list, err = client.ListRepos(ctx, market.ListOptions{From: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), list.Count)
+	assert.Empty(t, list.Data)
+}
+
+func TestServeListenError(t *testing.T) {
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	freeAddr := free.Addr().String()
+	assert.NoError(t, free.Close())
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer used.Close()
+
+	out := &bytes.Buffer{}
+	err = Serve(context.Background(), common.Options{IOStreams: genericclioptions.IOStreams{Out: out, ErrOut: out}}, New(), freeAddr, used.Addr().String())
+	assert.Error(t, err)
+	assert.Empty(t, out.String())
+
+	// the listener opened before the failure is closed
+	l, err := net.Listen("tcp", freeAddr)
+	assert.NoError(t, err)
+	assert.NoError(t, l.Close())
+}
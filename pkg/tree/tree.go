@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tree prints the topology of the resources visible to the current user.
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/chaincode"
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/relation"
+)
+
+// status markers of resources
+const (
+	markerReady   = "✓"
+	markerFailed  = "✗"
+	markerPending = "…"
+)
+
+// marker returns the marker of status, empty if the resource reports no status
+func marker(status string) string {
+	switch status {
+	case "":
+		return ""
+	case common.StatusDeployed, chaincode.PhaseCommitted, chaincodebuild.StatusPipelineSucceeded:
+		return markerReady
+	case common.StatusError, chaincodebuild.StatusPipelineFailed:
+		return markerFailed
+	}
+	return markerPending
+}
+
+// Roots returns the trees starting from organizations orgs, down to depth levels. depth < 0 means no limit.
+func Roots(ctx context.Context, cli dynamic.Interface, orgs []string, depth int) ([]*relation.Node, error) {
+	g := relation.NewGraph(cli)
+	roots := make([]*relation.Node, 0, len(orgs))
+	for _, name := range orgs {
+		root, err := g.Tree(ctx, relation.Ref{Kind: relation.KindOrganization, Name: name}, depth)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+func label(n *relation.Node) string {
+	switch {
+	case n.Missing:
+		return n.Ref.String() + " " + markerFailed + " not found"
+	case n.Status == "":
+		return n.Ref.String()
+	}
+	return fmt.Sprintf("%s %s %s", n.Ref, marker(n.Status), n.Status)
+}
+
+// PrintText prints roots as indented trees. Resources shared by several organizations are expanded
+// the first time only, later occurrences are marked as shown above.
+func PrintText(out io.Writer, roots []*relation.Node) {
+	shown := map[relation.Ref]bool{}
+	var walk func(n *relation.Node, prefix string, childPrefix string)
+	walk = func(n *relation.Node, prefix string, childPrefix string) {
+		if shown[n.Ref] && len(n.Children) > 0 {
+			fmt.Fprintf(out, "%s%s (shown above)\n", prefix, label(n))
+			return
+		}
+		shown[n.Ref] = true
+		fmt.Fprintf(out, "%s%s\n", prefix, label(n))
+		for i, child := range n.Children {
+			if i == len(n.Children)-1 {
+				walk(child, childPrefix+"└── ", childPrefix+"    ")
+			} else {
+				walk(child, childPrefix+"├── ", childPrefix+"│   ")
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(root, "", "")
+	}
+}
+
+// PrintDot prints roots as a graphviz digraph, each resource is a node colored by its status
+func PrintDot(out io.Writer, roots []*relation.Node) {
+	colors := map[string]string{
+		markerReady:   "green",
+		markerFailed:  "red",
+		markerPending: "orange",
+		"":            "black",
+	}
+	quote := func(s string) string {
+		return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+	}
+	fmt.Fprintln(out, "digraph bestchains {")
+	fmt.Fprintln(out, "  rankdir=LR;")
+	fmt.Fprintln(out, "  node [shape=box];")
+	nodes := map[relation.Ref]bool{}
+	edges := map[[2]relation.Ref]bool{}
+	var walk func(n *relation.Node)
+	walk = func(n *relation.Node) {
+		if !nodes[n.Ref] {
+			nodes[n.Ref] = true
+			text, color := n.Ref.String(), colors[marker(n.Status)]
+			switch {
+			case n.Missing:
+				text, color = text+`\nnot found`, colors[markerFailed]
+			case n.Status != "":
+				text += `\n` + n.Status
+			}
+			fmt.Fprintf(out, "  %s [label=%s, color=%s];\n", quote(n.Ref.String()), quote(text), color)
+		}
+		for _, child := range n.Children {
+			walk(child)
+			if edge := [2]relation.Ref{n.Ref, child.Ref}; !edges[edge] {
+				edges[edge] = true
+				fmt.Fprintf(out, "  %s -> %s;\n", quote(n.Ref.String()), quote(child.Ref.String()))
+			}
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+	fmt.Fprintln(out, "}")
+}
+
+func NewTreeCmd(option common.Options) *cobra.Command {
+	var (
+		orgs   []string
+		depth  int
+		output string
+	)
+	cmd := &cobra.Command{
+		Use:   "tree [--org ORG] [--depth N] [-o json|dot]",
+		Short: "Show the topology of the resources of the organizations of the current user",
+		Long: `Show the topology of the resources of the organizations of the current user
+
+The tree starts from the organizations of the current user and walks federations, networks, channels,
+then chaincodes and endorsepolicies of each channel. Statuses are marked by ✓ for ready, ✗ for failed
+and … for in progress. Render the dot output with graphviz, such as:
+
+  bc-cli tree -o dot | dot -Tsvg > topology.svg
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch output {
+			case "", "json", "dot":
+			default:
+				return fmt.Errorf("unknown output format %q, must be json or dot", output)
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if len(orgs) == 0 {
+				if orgs, err = org.ListUserOrganizations(cli, viper.GetString("auth.username")); err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					return err
+				}
+			}
+			roots, err := Roots(cmd.Context(), cli, orgs, depth)
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			switch output {
+			case "json":
+				data, err := json.MarshalIndent(roots, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(option.Out, string(data))
+			case "dot":
+				PrintDot(option.Out, roots)
+			default:
+				PrintText(option.Out, roots)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&orgs, "org", nil, "organizations to start from, default to all organizations of the current user")
+	cmd.Flags().IntVar(&depth, "depth", -1, "max depth of the tree, negative means no limit")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "output format, one of json and dot")
+	return cmd
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bestchains/bc-cli/pkg/internal/fixture"
+	"github.com/bestchains/bc-cli/pkg/relation"
+)
+
+func roots(t *testing.T) []*relation.Node {
+	cli := fixture.NewClient(
+		fixture.NewObject(relation.KindOrganization, "org1", nil, map[string]interface{}{"type": "Deployed", "federations": []interface{}{"fed1"}}),
+		fixture.NewObject(relation.KindOrganization, "org2", nil, map[string]interface{}{"type": "Deployed", "federations": []interface{}{"fed1"}}),
+		fixture.NewObject(relation.KindFederation, "fed1", nil, map[string]interface{}{"type": "Deployed", "networks": []interface{}{"net1"}}),
+		fixture.NewObject(relation.KindNetwork, "net1", nil, map[string]interface{}{"type": "Created", "channels": []interface{}{"ch1", "ch2"}}),
+		fixture.NewObject(relation.KindChannel, "ch1", nil, map[string]interface{}{"type": "Error"}),
+		fixture.NewObject(relation.KindChaincode, "ch1-basic", map[string]interface{}{"channel": "ch1"}, map[string]interface{}{"phase": "Committed"}),
+		fixture.NewObject(relation.KindEndorsePolicy, "ep1", map[string]interface{}{"channel": "ch1"}, nil),
+	)
+	result, err := Roots(context.Background(), cli, []string{"org1", "org2"}, -1)
+	assert.NoError(t, err)
+	return result
+}
+
+func TestPrintText(t *testing.T) {
+	out := &bytes.Buffer{}
+	PrintText(out, roots(t))
+	assert.Equal(t, `organization/org1 ✓ Deployed
+└── federation/fed1 ✓ Deployed
+    └── network/net1 … Created
+        ├── channel/ch1 ✗ Error
+        │   ├── chaincode/ch1-basic ✓ Committed
+        │   └── endorsepolicy/ep1
+        └── channel/ch2 ✗ not found
+organization/org2 ✓ Deployed
+└── federation/fed1 ✓ Deployed (shown above)
+`, out.String())
+}
+
+func TestPrintDot(t *testing.T) {
+	out := &bytes.Buffer{}
+	PrintDot(out, roots(t))
+	assert.Equal(t, `digraph bestchains {
+  rankdir=LR;
+  node [shape=box];
+  "organization/org1" [label="organization/org1\nDeployed", color=green];
+  "federation/fed1" [label="federation/fed1\nDeployed", color=green];
+  "network/net1" [label="network/net1\nCreated", color=orange];
+  "channel/ch1" [label="channel/ch1\nError", color=red];
+  "chaincode/ch1-basic" [label="chaincode/ch1-basic\nCommitted", color=green];
+  "channel/ch1" -> "chaincode/ch1-basic";
+  "endorsepolicy/ep1" [label="endorsepolicy/ep1", color=black];
+  "channel/ch1" -> "endorsepolicy/ep1";
+  "network/net1" -> "channel/ch1";
+  "channel/ch2" [label="channel/ch2\nnot found", color=red];
+  "network/net1" -> "channel/ch2";
+  "federation/fed1" -> "network/net1";
+  "organization/org1" -> "federation/fed1";
+  "organization/org2" [label="organization/org2\nDeployed", color=green];
+  "organization/org2" -> "federation/fed1";
+}
+`, out.String())
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(roots(t)[1])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"kind": "Organization", "name": "org2", "status": "Deployed", "children": [
+		{"kind": "Federation", "name": "fed1", "status": "Deployed", "children": [
+			{"kind": "Network", "name": "net1", "status": "Created", "children": [
+				{"kind": "Channel", "name": "ch1", "status": "Error", "children": [
+					{"kind": "Chaincode", "name": "ch1-basic", "status": "Committed"},
+					{"kind": "EndorsePolicy", "name": "ep1"}
+				]},
+				{"kind": "Channel", "name": "ch2", "missing": true}
+			]}
+		]}
+	]}`, string(data))
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/bestchains/bc-cli/pkg/auth"
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+// addAuthHeader adds the ID token of the current user to requests, replaced in tests
+var addAuthHeader = auth.AddAuthHeader
+
+// ClientOptions configures the timeout and retry behavior of a Client.
+type ClientOptions struct {
+	// Timeout is the time limit of a single request, including reading the response body.
+	// Zero means no timeout.
+	Timeout time.Duration
+	// MaxRetries is the max number of retries of an idempotent request
+	// when the connection fails or the server responds 5xx.
+	MaxRetries int
+	// RetryBackoff is the wait time before the first retry, it is doubled after each retry.
+	RetryBackoff time.Duration
+	// MaxRetryBackoff is the max wait time between two retries.
+	MaxRetryBackoff time.Duration
+	// Anonymous sends requests without the ID token of the current user,
+	// which must not leak to servers other than the platform, such as object stores.
+	Anonymous bool
+}
+
+// DefaultClientOptions is used by Do and ClientFor, which can be changed by global flags.
+var DefaultClientOptions = ClientOptions{
+	Timeout:         30 * time.Second,
+	MaxRetries:      3,
+	RetryBackoff:    500 * time.Millisecond,
+	MaxRetryBackoff: 5 * time.Second,
+}
+
+// Client is a http client for one server, which reuses connections among requests.
+type Client struct {
+	options ClientOptions
+	client  *http.Client
+}
+
+// NewClient creates a client for server with the tls settings of server in config.
+func NewClient(server string, options ClientOptions) (*Client, error) {
+	transport, err := common.NewTransport(server, common.TLSConfigForURL(server))
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		options: options,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   options.Timeout,
+		},
+	}, nil
+}
+
+var clients sync.Map
+
+// ClientFor returns the shared client of the server which rawURL belongs to.
+func ClientFor(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	key := u.Scheme + "://" + u.Host
+	if c, ok := clients.Load(key); ok {
+		return c.(*Client), nil
+	}
+	c, err := NewClient(key, DefaultClientOptions)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := clients.LoadOrStore(key, c)
+	return actual.(*Client), nil
+}
+
+// Do sends a request and returns the response body.
+// An *APIError is returned if the server does not respond 2xx.
+func (c *Client) Do(ctx context.Context, _url, method string, headers map[string]string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, _url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.Send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Send sends req with the auth header, idempotent requests are retried with exponential backoff
+// when the connection fails or the server responds 5xx.
+// The caller should close the response body. An *APIError is returned if the server does not respond 2xx.
+func (c *Client) Send(req *http.Request) (*http.Response, error) {
+	if !c.options.Anonymous {
+		addAuthHeader(req)
+	}
+	backoff := wait.Backoff{
+		Duration: c.options.RetryBackoff,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    c.options.MaxRetries + 1,
+		Cap:      c.options.MaxRetryBackoff,
+	}
+	retries := 0
+	if isIdempotent(req.Method) {
+		retries = c.options.MaxRetries
+	}
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := c.client.Do(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+		if err == nil {
+			data, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			err = newAPIError(resp.StatusCode, data)
+		}
+		if attempt >= retries || !retryable(err) {
+			return nil, err
+		}
+		delay := backoff.Step()
+		klog.V(2).Infof("%s %s failed: %s, retry in %s", req.Method, req.URL.Redacted(), err, delay)
+		select {
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("%w, last error: %s", req.Context().Err(), err)
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isIdempotent reports whether a request with method can be safely retried
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryable reports whether err is caused by connection errors or 5xx responses.
+// Errors which happen before connecting, such as invalid urls and unsupported schemes, are not retried.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	// *url.Error implements net.Error itself, so only the errors it wraps are checked
+	for {
+		urlErr, ok := err.(*url.Error)
+		if !ok {
+			break
+		}
+		err = urlErr.Err
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testOptions = ClientOptions{
+	Timeout:         time.Second,
+	MaxRetries:      2,
+	RetryBackoff:    time.Millisecond,
+	MaxRetryBackoff: 10 * time.Millisecond,
+}
+
+func TestClientRetry(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, testOptions)
+	assert.NoError(t, err)
+
+	// GET is retried until the server recovers
+	data, err := c.Do(context.Background(), server.URL, http.MethodGet, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", string(data))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// POST is never retried
+	atomic.StoreInt32(&calls, 0)
+	_, err = c.Do(context.Background(), server.URL, http.MethodPost, nil, []byte("value"))
+	assert.True(t, IsStatus(err, http.StatusServiceUnavailable))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClientAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"msg":"invalid nonce"}`))
+		case "/text":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("forbidden\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, testOptions)
+	assert.NoError(t, err)
+
+	_, err = c.Do(context.Background(), server.URL+"/json", http.MethodGet, nil, nil)
+	apiErr, ok := err.(*APIError)
+	assert.True(t, ok)
+	assert.Equal(t, "invalid nonce", apiErr.Message)
+	assert.Equal(t, "invalid nonce (400 Bad Request)", err.Error())
+
+	_, err = c.Do(context.Background(), server.URL+"/text", http.MethodGet, nil, nil)
+	assert.Equal(t, "server responded with 403 Forbidden: forbidden", err.Error())
+
+	_, err = c.Do(context.Background(), server.URL+"/none", http.MethodGet, nil, nil)
+	assert.True(t, IsNotFound(err))
+}
+
+func TestClientContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	options := testOptions
+	options.MaxRetries = 100
+	options.RetryBackoff = 50 * time.Millisecond
+	c, err := NewClient(server.URL, options)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.Do(ctx, server.URL, http.MethodGet, nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClientAnonymous(t *testing.T) {
+	defer func(add func(*http.Request)) { addAuthHeader = add }(addAuthHeader)
+	addAuthHeader = func(req *http.Request) { req.Header.Set("Authorization", "Bearer token") }
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, testOptions)
+	assert.NoError(t, err)
+	_, err = c.Do(context.Background(), server.URL, http.MethodGet, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token", got)
+
+	anonymous := testOptions
+	anonymous.Anonymous = true
+	c, err = NewClient(server.URL, anonymous)
+	assert.NoError(t, err)
+	_, err = c.Do(context.Background(), server.URL, http.MethodGet, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestRetryable(t *testing.T) {
+	c, err := NewClient("", testOptions)
+	assert.NoError(t, err)
+
+	// errors before connecting are not retried
+	for _, rawURL := range []string{"ftp://example.com", "http://[::1"} {
+		_, err = c.Do(context.Background(), rawURL, http.MethodGet, nil, nil)
+		assert.Error(t, err, rawURL)
+		assert.False(t, retryable(err), rawURL)
+	}
+
+	// connection errors are retried
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	closed := "http://" + listener.Addr().String()
+	listener.Close()
+	_, err = c.Do(context.Background(), closed, http.MethodGet, nil, nil)
+	assert.True(t, retryable(err))
+	assert.True(t, retryable(&url.Error{Op: "Get", URL: closed, Err: io.ErrUnexpectedEOF}))
+	assert.True(t, retryable(&url.Error{Op: "Get", URL: closed, Err: syscall.ECONNRESET}))
+	assert.False(t, retryable(&url.Error{Op: "Get", URL: closed, Err: errors.New("net/http: invalid header")}))
+}
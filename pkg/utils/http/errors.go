@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when the server responds with a non 2xx status code.
+type APIError struct {
+	// StatusCode is the http status code of the response
+	StatusCode int
+	// Message is the error message decoded from the bc-saas error response
+	Message string
+	// Body is the raw response body
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s (%d %s)", e.Message, e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	if body := strings.TrimSpace(string(e.Body)); body != "" {
+		return fmt.Sprintf("server responded with %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), body)
+	}
+	return fmt.Sprintf("server responded with %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// newAPIError decodes the bc-saas error response like `{"msg": "..."}`.
+// The raw body is kept if it is not a json object.
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Body: body}
+	var resp struct {
+		Msg     string `json:"msg"`
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil {
+		for _, msg := range []string{resp.Msg, resp.Message, resp.Error} {
+			if msg != "" {
+				apiErr.Message = msg
+				break
+			}
+		}
+	}
+	return apiErr
+}
+
+// IsStatus reports whether err is an APIError with the given status code.
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
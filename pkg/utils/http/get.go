@@ -17,46 +17,15 @@ limitations under the License.
 package http
 
 import (
-	"bytes"
-	"crypto/tls"
-	"fmt"
-	"io"
-	"net/http"
-
-	"github.com/bestchains/bc-cli/pkg/auth"
+	"context"
 )
 
-func Do(_url, method string, headers map[string]string, body []byte) ([]byte, error) {
-	req, err := http.NewRequest(method, _url, bytes.NewBuffer(body))
+// Do sends a request with the shared client of the server which _url belongs to,
+// and returns the response body. An *APIError is returned if the server does not respond 2xx.
+func Do(ctx context.Context, _url, method string, headers map[string]string, body []byte) ([]byte, error) {
+	c, err := ClientFor(_url)
 	if err != nil {
 		return nil, err
 	}
-	for k, v := range headers {
-		req.Header.Set(k, v)
-	}
-	auth.AddAuthHeader(req)
-
-	c := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				// FIXME: you should get the certificate information through the configuration file and should not skip the https authentication.
-				InsecureSkipVerify: true,
-			},
-		},
-	}
-
-	resp, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return data, fmt.Errorf("expect code 200 got %d", resp.StatusCode)
-	}
-	return data, nil
+	return c.Do(ctx, _url, method, headers, body)
 }
@@ -16,7 +16,12 @@ limitations under the License.
 
 package utils
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
 
 // GetNestedString returns the string value of a nested field.
 // Returns "" if value is not found or not a string.
@@ -37,3 +42,22 @@ func RemoveDuplicateForStringSlice(elements []string) []string {
 	}
 	return result
 }
+
+// ContainsString reports whether element is in elements.
+func ContainsString(elements []string, element string) bool {
+	for _, e := range elements {
+		if e == element {
+			return true
+		}
+	}
+	return false
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ResourceName joins parts with dashes into a valid resource name, such as basic-v1-0 from basic and v1.0.
+// Characters other than lowercase letters, digits and dashes are replaced by dashes.
+func ResourceName(parts ...string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(strings.Join(parts, "-")), "-")
+	return strings.Trim(name, "-")
+}
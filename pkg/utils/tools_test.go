@@ -93,3 +93,15 @@ func TestRemoveDuplicateForStringSlice(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceName(t *testing.T) {
+	for expected, parts := range map[string][]string{
+		"basic-v1-0":   {"basic", "v1.0"},
+		"ch1-my-cc":    {"Ch1", "My_CC"},
+		"fabcar-1-2-3": {"-fabcar", "1.2.3."},
+	} {
+		if name := ResourceName(parts...); name != expected {
+			t.Errorf("expected %s, but got %s", expected, name)
+		}
+	}
+}
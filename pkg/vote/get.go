@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vote
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/resource"
+	"github.com/bestchains/bc-cli/pkg/utils"
+)
+
+var voteGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Vote}
+
+// Kind is the kind of votes, which are in the namespaces of the organizations administered by the current user
+var Kind = resource.Kind{
+	Kind: "Vote",
+	Name: "vote",
+	GVR:  voteGVR,
+	Namespaces: func(ctx context.Context, cli dynamic.Interface) ([]string, error) {
+		return org.ListAdminOrganizations(cli, viper.GetString("auth.username"))
+	},
+	Columns: []resource.Column{
+		{Name: "Organization", Value: func(obj *unstructured.Unstructured) interface{} { return obj.GetNamespace() }},
+		{Name: "Proposal", Value: resource.Field("spec", "proposalName")},
+		{Name: "Decision", Value: func(obj *unstructured.Unstructured) interface{} { return Decision(obj) }},
+		{Name: "Phase", Value: resource.Field("status", "phase")},
+		{Name: "Description", Wide: true, Value: resource.Field("spec", "description")},
+	},
+}
+
+// ListVotes returns the votes in the namespace of organization, only votes of proposals are returned if any.
+func ListVotes(ctx context.Context, cli dynamic.Interface, organization string, proposals ...string) ([]unstructured.Unstructured, error) {
+	votes, err := cli.Resource(voteGVR).Namespace(organization).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(proposals) == 0 {
+		return votes.Items, nil
+	}
+	result := make([]unstructured.Unstructured, 0, len(votes.Items))
+	for _, vote := range votes.Items {
+		if utils.ContainsString(proposals, utils.GetNestedString(vote.Object, "spec", "proposalName")) {
+			result = append(result, vote)
+		}
+	}
+	return result, nil
+}
+
+// listOrgVotes returns the votes of each of orgs like ListVotes. The organizations whose votes can not be listed
+// do not stop the others, their errors are printed to errOut and returned as an aggregate.
+func listOrgVotes(ctx context.Context, cli dynamic.Interface, errOut io.Writer, orgs []string, proposals ...string) ([]unstructured.Unstructured, error) {
+	var (
+		result []unstructured.Unstructured
+		errs   []error
+	)
+	for _, o := range utils.RemoveDuplicateForStringSlice(orgs) {
+		votes, err := ListVotes(ctx, cli, o, proposals...)
+		if err != nil {
+			fmt.Fprintln(errOut, err)
+			errs = append(errs, err)
+			continue
+		}
+		result = append(result, votes...)
+	}
+	return result, utilerrors.Reduce(utilerrors.NewAggregate(errs))
+}
+
+func NewVoteGetCmd(option common.Options) *cobra.Command {
+	var orgs []string
+	flags := resource.NewGetFlags()
+	cmd := &cobra.Command{
+		Use:   "vote [PROPOSAL]... [--org ORG]",
+		Short: "Get the votes of the organizations administered by the current user",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if len(orgs) == 0 {
+				orgs, err = org.ListAdminOrganizations(cli, viper.GetString("auth.username"))
+				if err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					return err
+				}
+			}
+
+			list := corev1.List{
+				TypeMeta: v1.TypeMeta{
+					Kind:       "List",
+					APIVersion: "v1",
+				},
+				ListMeta: v1.ListMeta{},
+			}
+			votes, listErr := listOrgVotes(cmd.Context(), cli, option.ErrOut, orgs, args...)
+			for i := range votes {
+				list.Items = append(list.Items, runtime.RawExtension{Object: &votes[i]})
+			}
+
+			var obj runtime.Object
+			if len(list.Items) != 1 {
+				obj, err = common.ListToObj(list)
+				if err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					return err
+				}
+			} else {
+				obj = list.Items[0].Object
+			}
+
+			p, err := flags.ToPrinter(Kind)
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if err := p.PrintObj(obj, option.Out); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			// the votes of the other organizations are printed, the failed ones still fail the command
+			return listErr
+		},
+	}
+	flags.PrintFlags.AddFlags(cmd)
+	cmd.Flags().StringSliceVar(&orgs, "org", nil, "organizations to get votes of, default to the organizations administered by the current user")
+
+	return cmd
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/org"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+)
+
+var proposalGVR = schema.GroupVersionResource{Group: common.IBPGroup, Version: common.IBPVersion, Resource: common.Proposal}
+
+// FindVote returns the vote of organization on proposal
+func FindVote(ctx context.Context, cli dynamic.Interface, organization string, proposalName string) (*unstructured.Unstructured, error) {
+	votes, err := ListVotes(ctx, cli, organization, proposalName)
+	if err != nil {
+		return nil, err
+	}
+	if len(votes) == 0 {
+		return nil, fmt.Errorf("organization %s has no vote on proposal %s", organization, proposalName)
+	}
+	return &votes[0], nil
+}
+
+// Decision returns Approved or Rejected by spec.decision of vote, <none> if it is not decided yet
+func Decision(vote *unstructured.Unstructured) string {
+	decision, found, _ := unstructured.NestedBool(vote.Object, "spec", "decision")
+	switch {
+	case !found:
+		return "<none>"
+	case decision:
+		return "Approved"
+	}
+	return "Rejected"
+}
+
+// Vote sets the decision and reason of vote, an error is returned if the vote is already decided.
+func Vote(ctx context.Context, cli dynamic.Interface, vote *unstructured.Unstructured, decision bool, reason string) (*unstructured.Unstructured, error) {
+	if _, found, _ := unstructured.NestedBool(vote.Object, "spec", "decision"); found {
+		return nil, fmt.Errorf("vote %s/%s is already decided", vote.GetNamespace(), vote.GetName())
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"decision":    decision,
+			"description": reason,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cli.Resource(voteGVR).Namespace(vote.GetNamespace()).Patch(ctx, vote.GetName(), types.MergePatchType, patch, v1.PatchOptions{})
+}
+
+// voterOrganization returns the only organization administered by the current user which has a vote on proposal
+func voterOrganization(ctx context.Context, cli dynamic.Interface, proposalName string) (string, error) {
+	username := viper.GetString("auth.username")
+	orgs, err := org.ListAdminOrganizations(cli, username)
+	if err != nil {
+		return "", err
+	}
+	voters := make([]string, 0, len(orgs))
+	for _, o := range orgs {
+		votes, err := ListVotes(ctx, cli, o, proposalName)
+		if err != nil {
+			return "", err
+		}
+		if len(votes) > 0 {
+			voters = append(voters, o)
+		}
+	}
+	switch len(voters) {
+	case 0:
+		return "", fmt.Errorf("no organization administered by user %s can vote on proposal %s", username, proposalName)
+	case 1:
+		return voters[0], nil
+	}
+	return "", fmt.Errorf("organizations %v can vote on proposal %s, choose one with --org", voters, proposalName)
+}
+
+func NewVoteCmd(option common.Options) *cobra.Command {
+	var (
+		organization    string
+		approve, reject bool
+		reason          string
+	)
+	cmd := &cobra.Command{
+		Use:   "vote PROPOSAL (--approve | --reject) [--org ORG] [--reason TEXT]",
+		Short: "Vote on a proposal for an organization",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if approve == reject {
+				return fmt.Errorf("exactly one of --approve and --reject is required")
+			}
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			p, err := cli.Resource(proposalGVR).Get(cmd.Context(), args[0], v1.GetOptions{})
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			if deadline := proposal.Deadline(p); !deadline.IsZero() && deadline.Before(time.Now()) {
+				return fmt.Errorf("proposal %s expired at %s", p.GetName(), deadline.Local().Format(time.RFC3339))
+			}
+			if organization == "" {
+				if organization, err = voterOrganization(cmd.Context(), cli, p.GetName()); err != nil {
+					return err
+				}
+			}
+			vote, err := FindVote(cmd.Context(), cli, organization, p.GetName())
+			if err != nil {
+				return err
+			}
+			if _, err := Vote(cmd.Context(), cli, vote, approve, reason); err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			decision := "approved"
+			if reject {
+				decision = "rejected"
+			}
+			fmt.Fprintf(option.Out, "vote/%s %s by organization %s\n", vote.GetName(), decision, organization)
+
+			// the tally is updated by the operator, it may not contain the vote above yet
+			if p, err = cli.Resource(proposalGVR).Get(cmd.Context(), p.GetName(), v1.GetOptions{}); err == nil {
+				proposal.PrintSummary(option.Out, p, time.Now())
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&organization, "org", "", "organization to vote for, default to the only organization administered by the current user which can vote")
+	cmd.Flags().BoolVar(&approve, "approve", false, "approve the proposal")
+	cmd.Flags().BoolVar(&reject, "reject", false, "reject the proposal")
+	cmd.Flags().StringVar(&reason, "reason", "", "reason of the decision")
+	cmd.MarkFlagsMutuallyExclusive("approve", "reject")
+	return cmd
+}
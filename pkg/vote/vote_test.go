@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/bestchains/bc-cli/pkg/common"
+)
+
+func newVote(namespace, name, proposal string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": common.IBPGroup + "/" + common.IBPVersion,
+		"kind":       "Vote",
+		"spec": map[string]interface{}{
+			"proposalName":     proposal,
+			"organizationName": namespace,
+		},
+	}}
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestVote(t *testing.T) {
+	ctx := context.Background()
+	cli := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		voteGVR: "VoteList",
+	}, newVote("org1", "vote-a", "pro-a"), newVote("org1", "vote-b", "pro-b"), newVote("org2", "vote-c", "pro-a"))
+
+	votes, err := ListVotes(ctx, cli, "org1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(votes))
+
+	vote, err := FindVote(ctx, cli, "org1", "pro-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "vote-a", vote.GetName())
+	_, err = FindVote(ctx, cli, "org2", "pro-b")
+	assert.Error(t, err)
+
+	// an organization whose votes can not be listed does not stop the others
+	cli.PrependReactor("list", "votes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "org3" {
+			return true, nil, errors.New("forbidden")
+		}
+		return false, nil, nil
+	})
+	errOut := &bytes.Buffer{}
+	votes, err = listOrgVotes(ctx, cli, errOut, []string{"org1", "org3", "org2"}, "pro-a")
+	assert.EqualError(t, err, "forbidden")
+	assert.Equal(t, "forbidden\n", errOut.String())
+	assert.Equal(t, 2, len(votes))
+
+	voted, err := Vote(ctx, cli, vote, false, "not now")
+	assert.NoError(t, err)
+	decision, found, _ := unstructured.NestedBool(voted.Object, "spec", "decision")
+	assert.True(t, found)
+	assert.False(t, decision)
+	assert.Equal(t, "not now", voted.Object["spec"].(map[string]interface{})["description"])
+
+	_, err = Vote(ctx, cli, voted, true, "")
+	assert.ErrorContains(t, err, "already decided")
+}
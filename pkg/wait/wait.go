@@ -0,0 +1,336 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package wait blocks until bestchains resources meet a condition, such as a chaincodebuild
+// which finishes its pipeline or a network which is deployed.
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/bestchains/bc-cli/pkg/chaincode"
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/common"
+	"github.com/bestchains/bc-cli/pkg/kinds"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+)
+
+const (
+	// ConditionReady is the condition of resources which are ready to use,
+	// such as deployed networks, succeeded chaincodebuilds and committed chaincodes
+	ConditionReady = "Ready"
+
+	kindProposal = "Proposal"
+)
+
+// failedStatuses are the status types and phases from which resources do not recover by themselves
+var failedStatuses = map[string]bool{
+	common.StatusError:                  true,
+	chaincodebuild.StatusPipelineFailed: true,
+	"Failed":                            true,
+	"Expired":                           true,
+}
+
+// ParseRef parses KIND/NAME, KIND can be a short name such as ccb or ep
+func ParseRef(s string) (relation.Ref, error) {
+	kind, name, ok := strings.Cut(s, "/")
+	if !ok || name == "" {
+		return relation.Ref{}, fmt.Errorf("invalid resource %q, must be KIND/NAME", s)
+	}
+	k, err := kinds.Registry.Lookup(kind)
+	if err != nil {
+		return relation.Ref{}, err
+	}
+	if k.Namespaced() {
+		return relation.Ref{}, fmt.Errorf("kind %q is namespaced, which can not be waited for", kind)
+	}
+	return relation.Ref{Kind: k.Kind, Name: name}, nil
+}
+
+func gvr(kind string) schema.GroupVersionResource {
+	k, _ := kinds.Registry.ForKind(kind)
+	return k.GVR
+}
+
+// Condition is what resources are waited for
+type Condition struct {
+	// Delete waits for the resources to be deleted
+	Delete bool
+	// Type is the type of a condition in status.conditions, such as Ready
+	Type string
+	// Path is the path of a field, such as status.phase
+	Path []string
+	// Value is the expected status of the condition or value of the field
+	Value string
+}
+
+// ParseCondition parses the value of --for, which is one of
+// delete, condition=TYPE[=STATUS] and FIELD.PATH=VALUE such as status.phase=Committed
+func ParseCondition(s string) (Condition, error) {
+	if s == "delete" {
+		return Condition{Delete: true}, nil
+	}
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" || value == "" {
+		return Condition{}, fmt.Errorf("invalid condition %q, must be delete, condition=TYPE[=STATUS] or FIELD.PATH=VALUE", s)
+	}
+	if key == "condition" {
+		conditionType, status, ok := strings.Cut(value, "=")
+		if !ok {
+			status = "True"
+		}
+		return Condition{Type: conditionType, Value: status}, nil
+	}
+	return Condition{Path: strings.Split(strings.TrimPrefix(key, "."), "."), Value: value}, nil
+}
+
+func (c Condition) String() string {
+	switch {
+	case c.Delete:
+		return "delete"
+	case c.Type != "":
+		return fmt.Sprintf("condition %s=%s", c.Type, c.Value)
+	}
+	return fmt.Sprintf("%s=%s", strings.Join(c.Path, "."), c.Value)
+}
+
+// current returns what the condition checks in obj, for messages on timeout
+func (c Condition) current(obj *unstructured.Unstructured) string {
+	if c.Path != nil {
+		return fmt.Sprintf("%s is %s", strings.Join(c.Path, "."), orNone(fieldValue(obj, c.Path)))
+	}
+	return fmt.Sprintf("status is %s", orNone(relation.Status(obj)))
+}
+
+// Met reports whether obj meets the condition, Delete is never met by an existing resource.
+// Besides status.conditions, condition Ready is met by the ready status of each kind.
+func (c Condition) Met(obj *unstructured.Unstructured) bool {
+	switch {
+	case c.Delete:
+		return false
+	case c.Path != nil:
+		return fieldValue(obj, c.Path) == c.Value
+	}
+	if status, found := conditionStatus(obj, c.Type); found {
+		return strings.EqualFold(status, c.Value)
+	}
+	if c.Type == ConditionReady && strings.EqualFold(c.Value, "True") {
+		return Ready(obj)
+	}
+	return false
+}
+
+// Ready reports whether obj is ready to use
+func Ready(obj *unstructured.Unstructured) bool {
+	switch obj.GetKind() {
+	case relation.KindChaincode:
+		return chaincode.Committed(obj)
+	case kindProposal:
+		return proposal.Phase(obj) == proposal.PhaseFinished && !proposal.Failed(obj)
+	}
+	switch relation.Status(obj) {
+	case common.StatusDeployed, chaincodebuild.StatusPipelineSucceeded:
+		return true
+	}
+	return false
+}
+
+// Failed returns why obj fails, empty if it does not
+func Failed(obj *unstructured.Unstructured) string {
+	if obj.GetKind() == kindProposal && proposal.Failed(obj) {
+		return "rejected by voters"
+	}
+	status := relation.Status(obj)
+	if !failedStatuses[status] {
+		return ""
+	}
+	reason, _, _ := unstructured.NestedString(obj.Object, "status", "reason")
+	message, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+	return strings.TrimSpace(strings.Join([]string{status, reason, message}, " "))
+}
+
+func fieldValue(obj *unstructured.Unstructured, path []string) string {
+	value, found, err := unstructured.NestedFieldNoCopy(obj.Object, path...)
+	if !found || err != nil || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func conditionStatus(obj *unstructured.Unstructured, conditionType string) (string, bool) {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if t, _, _ := unstructured.NestedString(c, "type"); strings.EqualFold(t, conditionType) {
+			status, _, _ := unstructured.NestedString(c, "status")
+			return status, true
+		}
+	}
+	return "", false
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// errMet stops watching once the condition is met
+var errMet = errors.New("condition met")
+
+// For watches the resource ref until it meets condition, it fails once the resource reports a failure
+// which the condition does not expect or it is deleted unexpectedly. timeout 0 means waiting until ctx is done.
+func For(ctx context.Context, cli dynamic.Interface, ref relation.Ref, condition Condition, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	ri := cli.Resource(gvr(ref.Kind))
+	obj, err := ri.Get(ctx, ref.Name, v1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) && condition.Delete {
+			return nil
+		}
+		return err
+	}
+
+	check := func(eventType watch.EventType, current *unstructured.Unstructured) error {
+		obj = current
+		if eventType == watch.Deleted {
+			if condition.Delete {
+				return errMet
+			}
+			return fmt.Errorf("%s was deleted while waiting for %s", ref, condition)
+		}
+		if condition.Met(current) {
+			return errMet
+		}
+		if reason := Failed(current); reason != "" && !condition.Delete {
+			return fmt.Errorf("%s failed: %s", ref, reason)
+		}
+		return nil
+	}
+	if err := check(watch.Added, obj); err != nil {
+		if errors.Is(err, errMet) {
+			return nil
+		}
+		return err
+	}
+
+	options := v1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", ref.Name).String(),
+		ResourceVersion: obj.GetResourceVersion(),
+	}
+	err = common.Watch(ctx, ri, options, common.HasName([]string{ref.Name}), check)
+	switch {
+	case errors.Is(err, errMet):
+		return nil
+	case err != nil:
+		return err
+	case ctx.Err() != nil:
+		if condition.Delete {
+			return fmt.Errorf("timed out waiting for %s to be deleted", ref)
+		}
+		return fmt.Errorf("timed out waiting for %s on %s, current %s", condition, ref, condition.current(obj))
+	}
+	return nil
+}
+
+func NewWaitCmd(option common.Options) *cobra.Command {
+	var (
+		forCondition string
+		timeout      time.Duration
+	)
+	cmd := &cobra.Command{
+		Use:   "wait KIND/NAME... --for=delete|--for=condition=TYPE[=STATUS]|--for=FIELD.PATH=VALUE [--timeout 30s]",
+		Short: "Wait for resources to meet a condition",
+		Long: `Wait for resources to meet a condition.
+
+The command exits with a non-zero code if the timeout is reached, or a resource fails,
+such as a chaincodebuild whose pipeline fails, before it meets the condition.
+Condition Ready is met by deployed organizations, federations, networks and channels,
+succeeded chaincodebuilds, committed chaincodes and succeeded proposals.
+
+Examples:
+  # Wait for a chaincodebuild to finish
+  bc-cli wait ccb/ccb-sample --for=status.type=PipelineRunSucceeded --timeout 10m
+
+  # Wait for a network to be ready
+  bc-cli wait network/net1 --for=condition=Ready
+
+  # Wait for a channel to be deleted
+  bc-cli wait channel/ch1 --for=delete`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			condition, err := ParseCondition(forCondition)
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			refs := make([]relation.Ref, 0, len(args))
+			for _, arg := range args {
+				ref, err := ParseRef(arg)
+				if err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					return err
+				}
+				refs = append(refs, ref)
+			}
+			cmd.SilenceUsage = true
+
+			cli, err := common.GetDynamicClient()
+			if err != nil {
+				fmt.Fprintln(option.ErrOut, err)
+				return err
+			}
+			for _, ref := range refs {
+				if err := For(cmd.Context(), cli, ref, condition, timeout); err != nil {
+					fmt.Fprintln(option.ErrOut, err)
+					return err
+				}
+				if condition.Delete {
+					fmt.Fprintf(option.Out, "%s deleted\n", ref)
+				} else {
+					fmt.Fprintf(option.Out, "%s condition met\n", ref)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&forCondition, "for", "", "the condition to wait on: delete, condition=TYPE[=STATUS] or FIELD.PATH=VALUE")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "the length of time to wait for each resource, zero means waiting forever")
+	_ = cmd.MarkFlagRequired("for")
+	return cmd
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Bestchains Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wait
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/bestchains/bc-cli/pkg/chaincodebuild"
+	"github.com/bestchains/bc-cli/pkg/proposal"
+	"github.com/bestchains/bc-cli/pkg/relation"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("ccb/build1")
+	assert.NoError(t, err)
+	assert.Equal(t, relation.Ref{Kind: relation.KindChaincodeBuild, Name: "build1"}, ref)
+	ref, err = ParseRef("Networks/net1")
+	assert.NoError(t, err)
+	assert.Equal(t, relation.Ref{Kind: relation.KindNetwork, Name: "net1"}, ref)
+
+	_, err = ParseRef("net1")
+	assert.ErrorContains(t, err, "KIND/NAME")
+	_, err = ParseRef("pod/p1")
+	assert.ErrorContains(t, err, "unknown kind")
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := map[string]Condition{
+		"delete":                 {Delete: true},
+		"condition=Ready":        {Type: "Ready", Value: "True"},
+		"condition=Ready=false":  {Type: "Ready", Value: "false"},
+		"status.phase=Committed": {Path: []string{"status", "phase"}, Value: "Committed"},
+		".status.sequence=2":     {Path: []string{"status", "sequence"}, Value: "2"},
+	}
+	for s, expected := range tests {
+		c, err := ParseCondition(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, c, s)
+	}
+	for _, s := range []string{"", "deleted", "status.phase=", "=Ready"} {
+		_, err := ParseCondition(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func newBuild(name, status string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("ibp.com/v1beta1")
+	obj.SetKind(relation.KindChaincodeBuild)
+	obj.SetName(name)
+	if status != "" {
+		_ = unstructured.SetNestedField(obj.Object, status, "status", "type")
+	}
+	return obj
+}
+
+func TestMet(t *testing.T) {
+	ready := Condition{Type: ConditionReady, Value: "True"}
+	assert.False(t, ready.Met(newBuild("b", chaincodebuild.StatusPipelineRunning)))
+	assert.True(t, ready.Met(newBuild("b", chaincodebuild.StatusPipelineSucceeded)))
+
+	// status.conditions take precedence over the ready status of the kind
+	build := newBuild("b", chaincodebuild.StatusPipelineSucceeded)
+	_ = unstructured.SetNestedSlice(build.Object, []interface{}{map[string]interface{}{"type": "Ready", "status": "False"}}, "status", "conditions")
+	assert.False(t, ready.Met(build))
+	assert.True(t, Condition{Type: ConditionReady, Value: "false"}.Met(build))
+
+	assert.True(t, Condition{Path: []string{"status", "type"}, Value: chaincodebuild.StatusPipelineFailed}.Met(newBuild("b", chaincodebuild.StatusPipelineFailed)))
+	assert.Equal(t, chaincodebuild.StatusPipelineFailed, Failed(newBuild("b", chaincodebuild.StatusPipelineFailed)))
+	assert.Empty(t, Failed(newBuild("b", chaincodebuild.StatusPipelineRunning)))
+
+	// proposals are ready once they finish without being rejected
+	p := proposal.NewProposal(proposal.Options{Name: "p", Policy: proposal.PolicyAll}, proposal.SourceCreateFederation, map[string]interface{}{})
+	_ = unstructured.SetNestedSlice(p.Object, []interface{}{
+		map[string]interface{}{"organizationName": "org1", "decision": true},
+		map[string]interface{}{"organizationName": "org2", "decision": true},
+	}, "status", "votes")
+	_ = unstructured.SetNestedField(p.Object, proposal.PhaseVoting, "status", "phase")
+	assert.False(t, ready.Met(p))
+	_ = unstructured.SetNestedField(p.Object, proposal.PhaseFinished, "status", "phase")
+	assert.True(t, ready.Met(p))
+	_ = unstructured.SetNestedSlice(p.Object, []interface{}{
+		map[string]interface{}{"organizationName": "org1", "decision": true},
+		map[string]interface{}{"organizationName": "org2", "decision": false},
+	}, "status", "votes")
+	assert.False(t, ready.Met(p))
+	assert.Equal(t, "rejected by voters", Failed(p))
+}
+
+func TestFor(t *testing.T) {
+	gvr := relation.GVR(relation.KindChaincodeBuild)
+	newClient := func(events ...watch.Event) *fake.FakeDynamicClient {
+		cli := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{gvr: "ChaincodeBuildList"},
+			newBuild("b1", chaincodebuild.StatusPipelineRunning))
+		w := watch.NewFakeWithChanSize(len(events), false)
+		for _, e := range events {
+			w.Action(e.Type, e.Object)
+		}
+		cli.PrependWatchReactor("*", k8stesting.DefaultWatchReactor(w, nil))
+		return cli
+	}
+	ref := relation.Ref{Kind: relation.KindChaincodeBuild, Name: "b1"}
+	succeeded := Condition{Path: []string{"status", "type"}, Value: chaincodebuild.StatusPipelineSucceeded}
+
+	cli := newClient(
+		watch.Event{Type: watch.Modified, Object: newBuild("other", chaincodebuild.StatusPipelineSucceeded)},
+		watch.Event{Type: watch.Modified, Object: newBuild("b1", chaincodebuild.StatusPipelineSucceeded)})
+	assert.NoError(t, For(context.Background(), cli, ref, succeeded, time.Second))
+
+	cli = newClient(watch.Event{Type: watch.Modified, Object: newBuild("b1", chaincodebuild.StatusPipelineFailed)})
+	assert.ErrorContains(t, For(context.Background(), cli, ref, succeeded, time.Second), "chaincodebuild/b1 failed: PipelineRunFailed")
+
+	cli = newClient(watch.Event{Type: watch.Deleted, Object: newBuild("b1", chaincodebuild.StatusPipelineRunning)})
+	assert.ErrorContains(t, For(context.Background(), cli, ref, succeeded, time.Second), "was deleted")
+	cli = newClient(watch.Event{Type: watch.Deleted, Object: newBuild("b1", chaincodebuild.StatusPipelineRunning)})
+	assert.NoError(t, For(context.Background(), cli, ref, Condition{Delete: true}, time.Second))
+	assert.NoError(t, For(context.Background(), cli, relation.Ref{Kind: relation.KindChaincodeBuild, Name: "gone"}, Condition{Delete: true}, time.Second))
+
+	cli = newClient()
+	assert.EqualError(t, For(context.Background(), cli, ref, succeeded, 30*time.Millisecond),
+		"timed out waiting for status.type=PipelineRunSucceeded on chaincodebuild/b1, current status.type is PipelineRunning")
+}